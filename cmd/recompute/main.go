@@ -0,0 +1,75 @@
+// Команда recompute заново проводит все подтверждённые матчи по текущим правилам рейтинга
+// и пересобирает рейтинги игроков, изменения рейтинга в матчах, историю рейтинга
+// и турнирные таблицы.
+//
+//	go run ./cmd/recompute -dry-run          # показать разницу, ничего не сохраняя
+//	go run ./cmd/recompute -batch-size 10000 # пересчитать и сохранить
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"shumnaya/internal/config"
+	"shumnaya/internal/repository"
+	"shumnaya/internal/service"
+
+	"github.com/joho/godotenv"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "посчитать и вывести разницу по игрокам, ничего не сохраняя")
+	batchSize := flag.Int("batch-size", service.DefaultRecomputeBatchSize, "сколько матчей читать и сохранять за один запрос")
+	top := flag.Int("top", 0, "вывести только N игроков с наибольшим изменением рейтинга (0 — всех)")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	logger := config.InitLogger()
+
+	// Пакетные запросы огромные: не печатаем их целиком в лог медленных запросов
+	db := config.ConnectDB(logger)
+	db.Logger = gormlogger.Default.LogMode(gormlogger.Error)
+
+	recomputeService := service.NewRecomputeService(
+		db,
+		logger,
+		repository.NewMatchRepository(db, logger),
+		repository.NewPlayerRepository(db, logger),
+		repository.NewStandingRepository(db, logger),
+		repository.NewSeasonRepository(db, logger),
+		repository.NewRatingHistoryRepository(db, logger),
+	)
+
+	report, err := recomputeService.Recompute(service.RecomputeOptions{DryRun: *dryRun, BatchSize: *batchSize})
+	if err != nil {
+		log.Fatalf("пересчёт не выполнен: %v", err)
+	}
+
+	players := report.Players
+	if *top > 0 && len(players) > *top {
+		players = players[:*top]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "PLAYER\tNAME\tOLD\tNEW\tDELTA\t")
+	for _, p := range players {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%+d\t\n", p.PlayerID, p.Name, p.OldRating, p.NewRating, p.Delta)
+	}
+	w.Flush()
+
+	mode := "saved"
+	if report.DryRun {
+		mode = "dry run, nothing saved"
+	}
+
+	fmt.Printf("\n=== Recompute completed (%s) ===\n", mode)
+	fmt.Printf("Matches:         %d (rating changed: %d)\n", report.Matches, report.MatchesChanged)
+	fmt.Printf("Standings:       %d\n", report.Standings)
+	fmt.Printf("Players changed: %d\n", report.PlayersChanged)
+	fmt.Printf("Duration:        %s\n", report.Duration)
+}
@@ -111,7 +111,7 @@ func truncateAll(db *gorm.DB) error {
 		}
 	}
 
-	expected := []string{"matches", "standings", "seasons", "players"}
+	expected := []string{"standing_rank_snapshots", "season_awards", "season_results", "rating_history", "match_games", "matches", "standings", "seasons", "players"}
 	found := make([]string, 0, len(expected))
 	for _, name := range expected {
 		if existing[name] {
@@ -131,12 +131,12 @@ func truncateAll(db *gorm.DB) error {
 	if len(found) == 0 {
 		// If no tables found, try to auto-migrate models to ensure tables exist
 		log.Println("No existing target tables found; running AutoMigrate to create tables...")
-		if err := db.AutoMigrate(&models.Player{}, &models.Season{}, &models.Match{}, &models.Standing{}); err != nil {
+		if err := db.AutoMigrate(&models.Player{}, &models.Season{}, &models.Match{}, &models.MatchGame{}, &models.Standing{}, &models.RatingHistory{}, &models.SeasonResult{}, &models.SeasonAward{}, &models.StandingRankSnapshot{}); err != nil {
 			return fmt.Errorf("auto migrate failed: %w", err)
 		}
 
 		// assume default pluralized names created by GORM
-		found = []string{"matches", "standings", "seasons", "players"}
+		found = expected
 	}
 
 	sql := fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY CASCADE", strings.Join(found, ", "))
@@ -161,10 +161,13 @@ func seedPlayers(db *gorm.DB) []uint {
 			// CreatedAt / UpdatedAt honored by GORM when non-zero
 			// DeletedAt set for a small percentage to simulate soft delete
 			// Other fields
-			Name:         gofakeit.Name(),
-			Email:        fmt.Sprintf("user_%06d@test.com", i),
-			PasswordHash: fmt.Sprintf("hash_%s", gofakeit.UUID()),
-			Rating:       gofakeit.Number(1000, 2200),
+			Name:             gofakeit.Name(),
+			Email:            fmt.Sprintf("user_%06d@test.com", i),
+			PasswordHash:     fmt.Sprintf("hash_%s", gofakeit.UUID()),
+			Rating:           gofakeit.Number(1000, 2200),
+			RatingDeviation:  350,
+			RatingVolatility: 0.06,
+			EmailVerified:    true,
 		}
 		// Set embedded gorm.Model fields
 		p.Model.CreatedAt = created
@@ -208,6 +211,7 @@ func seedSeasons(db *gorm.DB) []seasonMeta {
 	fmt.Printf("Seeding seasons... 0/%d", total)
 
 	baseStart := time.Now().AddDate(-10, 0, 0)
+	winPoints := 1
 
 	for i := 0; i < total; i++ {
 		// Create seasons of ~3 months, spread over last ~10 years
@@ -220,19 +224,25 @@ func seedSeasons(db *gorm.DB) []seasonMeta {
 		name := fmt.Sprintf("Season %02d - %d", (i%12)+1, start.Year())
 
 		s := models.Season{
-			Name:      name,
-			StartDate: start,
-			EndDate:   end,
-			IsActive:  false,
+			Name:         name,
+			StartDate:    start,
+			EndDate:      end,
+			IsActive:     false,
+			BestOf:       5,
+			GamePoints:   11,
+			RatingEngine: "elo",
+			RatingReset:  "carry",
+			Points:       models.PointsScheme{Win: &winPoints, ForfeitWin: &winPoints},
+			TieBreakers:  models.DefaultTieBreakers,
 		}
 		// timestamps
 		created := gofakeit.DateRange(start.AddDate(0, -1, 0), start)
 		updated := gofakeit.DateRange(end, end.AddDate(0, 1, 0))
-		s.Model.CreatedAt = created
-		s.Model.UpdatedAt = updated
+		s.CreatedAt = created
+		s.UpdatedAt = updated
 		if gofakeit.Number(1, 100) <= softDeletePercent {
 			del := gofakeit.DateRange(created, updated)
-			s.Model.DeletedAt = gorm.DeletedAt{Time: del, Valid: true}
+			s.DeletedAt = gorm.DeletedAt{Time: del, Valid: true}
 		}
 
 		buf = append(buf, s)
@@ -257,12 +267,17 @@ func seedSeasons(db *gorm.DB) []seasonMeta {
 		}
 	}
 
-	// Mark a random season as active (or a few)
+	// Only one season may be active: the latest one that is still running
 	if len(result) > 0 {
-		activeN := int(math.Max(1, float64(len(result))/10)) // ~10%
-		for i := 0; i < activeN; i++ {
-			idx := gofakeit.Number(0, len(result)-1)
-			_ = db.Model(&models.Season{}).Where("id = ?", result[idx].ID).Update("is_active", true).Error
+		now := time.Now()
+		active := -1
+		for i, sm := range result {
+			if sm.EndDate.After(now) && (active < 0 || sm.StartDate.After(result[active].StartDate)) {
+				active = i
+			}
+		}
+		if active >= 0 {
+			_ = db.Model(&models.Season{}).Where("id = ?", result[active].ID).Update("is_active", true).Error
 		}
 	}
 
@@ -301,6 +316,10 @@ func seedStandings(db *gorm.DB, seasons []seasonMeta, playerIDs []uint) {
 				Losses:   losses,
 				Points:   points,
 				Rank:     count + 1,
+
+				Rating:           gofakeit.Number(900, 1600),
+				RatingDeviation:  350,
+				RatingVolatility: 0.06,
 			}
 			// timestamps near season end
 			created := gofakeit.DateRange(sm.StartDate, sm.EndDate)
@@ -341,6 +360,7 @@ func seedMatches(db *gorm.DB, seasons []seasonMeta, playerIDs []uint) {
 	fmt.Printf("Seeding matches... 0/%d", total)
 
 	buf := make([]models.Match, 0, batchSize)
+	venues := []string{"Main Hall", "Hall 2", "Community Center", "Park Tables"}
 
 	for i := 0; i < total; i++ {
 		// pick distinct players
@@ -361,10 +381,8 @@ func seedMatches(db *gorm.DB, seasons []seasonMeta, playerIDs []uint) {
 		created := gofakeit.DateRange(sm.StartDate, played)
 		updated := gofakeit.DateRange(played, sm.EndDate.AddDate(0, 1, 0))
 
-		// score like 3:0 .. 5:4 ensuring left>right
-		left := gofakeit.Number(1, 5)
-		right := gofakeit.Number(0, left-1)
-		score := fmt.Sprintf("%d:%d", left, right)
+		games, winnerGames, loserGames := randomGames(5, 11)
+		score := formatGames(games)
 
 		delta := gofakeit.Number(1, 25)
 
@@ -373,15 +391,19 @@ func seedMatches(db *gorm.DB, seasons []seasonMeta, playerIDs []uint) {
 			LoserID:            loserID,
 			SeasonID:           sm.ID,
 			Score:              score,
+			WinnerGames:        winnerGames,
+			LoserGames:         loserGames,
+			Games:              games,
 			WinnerRatingChange: delta,
 			LoserRatingChange:  -delta,
 			PlayedAt:           played,
+			Venue:              venues[gofakeit.Number(0, len(venues)-1)],
 		}
-		m.Model.CreatedAt = created
-		m.Model.UpdatedAt = updated
+		m.CreatedAt = created
+		m.UpdatedAt = updated
 		if gofakeit.Number(1, 100) <= softDeletePercent {
 			del := gofakeit.DateRange(created, updated)
-			m.Model.DeletedAt = gorm.DeletedAt{Time: del, Valid: true}
+			m.DeletedAt = gorm.DeletedAt{Time: del, Valid: true}
 		}
 
 		buf = append(buf, m)
@@ -403,3 +425,44 @@ func seedMatches(db *gorm.DB, seasons []seasonMeta, playerIDs []uint) {
 
 	fmt.Println(" ✓")
 }
+
+// randomGames генерирует валидный счёт best-of-N матча (очки победителя матча идут первыми).
+func randomGames(bestOf, pointsToWin int) ([]models.MatchGame, int, int) {
+	need := bestOf/2 + 1
+	loserGames := gofakeit.Number(0, need-1)
+
+	// последняя партия всегда за победителем, проигранные партии раскиданы среди остальных
+	outcomes := make([]bool, 0, need+loserGames)
+	for i := 0; i < need-1; i++ {
+		outcomes = append(outcomes, true)
+	}
+	for i := 0; i < loserGames; i++ {
+		outcomes = append(outcomes, false)
+	}
+	gofakeit.ShuffleAnySlice(outcomes)
+	outcomes = append(outcomes, true)
+
+	games := make([]models.MatchGame, len(outcomes))
+	for i, winnerWon := range outcomes {
+		hi, lo := pointsToWin, gofakeit.Number(0, pointsToWin-2)
+		if gofakeit.Number(1, 100) <= 15 {
+			// затяжная партия на балансе
+			hi = pointsToWin + gofakeit.Number(1, 5)
+			lo = hi - 2
+		}
+		if !winnerWon {
+			hi, lo = lo, hi
+		}
+		games[i] = models.MatchGame{Number: i + 1, WinnerPoints: hi, LoserPoints: lo}
+	}
+
+	return games, need, loserGames
+}
+
+func formatGames(games []models.MatchGame) string {
+	parts := make([]string, len(games))
+	for i, g := range games {
+		parts[i] = fmt.Sprintf("%d-%d", g.WinnerPoints, g.LoserPoints)
+	}
+	return strings.Join(parts, " ")
+}
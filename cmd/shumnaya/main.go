@@ -9,16 +9,32 @@
 // @host localhost:8080
 // @BasePath /
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access-токен в формате "Bearer <token>"
+
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"shumnaya/internal/config"
+	"shumnaya/internal/mailer"
 	"shumnaya/internal/models"
+	"shumnaya/internal/ratelimit"
 	"shumnaya/internal/repository"
 	"shumnaya/internal/service"
 	"shumnaya/internal/transport"
+	"shumnaya/internal/utils/jwtkeys"
+	"shumnaya/internal/worker"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -29,6 +45,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// shutdownTimeout — сколько ждать завершения активных запросов при остановке.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 
 	logger := config.InitLogger()
@@ -40,10 +59,18 @@ func main() {
 
 	db := config.ConnectDB(logger)
 
-	if err := db.AutoMigrate(&models.Match{}, &models.Player{}, &models.Season{}, &models.Standing{}); err != nil {
+	if err := repository.PrepareMigrations(db); err != nil {
+		logger.Error("ошибка подготовки миграции базы данных", "error", err)
+		log.Fatal("Ошибка подготовки миграции базы данных:", err)
+	}
+	if err := db.AutoMigrate(&models.Match{}, &models.MatchGame{}, &models.Player{}, &models.Season{}, &models.Standing{}, &models.RatingHistory{}, &models.SeasonResult{}, &models.SeasonAward{}, &models.StandingRankSnapshot{}, &models.RefreshToken{}, &models.RateLimitBucket{}, &models.RateLimitFailure{}, &models.SigningKey{}); err != nil {
 		logger.Error("ошибка миграции базы данных", "error", err)
 		log.Fatal("Ошибка миграции базы данных:", err)
 	}
+	if err := repository.EnsureIndexes(db); err != nil {
+		logger.Error("ошибка создания индексов", "error", err)
+		log.Fatal("Ошибка создания индексов:", err)
+	}
 
 	logger.Info("Миграция базы данных выполнена успешно")
 
@@ -51,24 +78,98 @@ func main() {
 	seasonRepo := repository.NewSeasonRepository(db, logger)
 	playerRepo := repository.NewPlayerRepository(db, logger)
 	standingRepo := repository.NewStandingRepository(db, logger)
+	ratingHistoryRepo := repository.NewRatingHistoryRepository(db, logger)
+	seasonResultRepo := repository.NewSeasonResultRepository(db, logger)
+	statsRepo := repository.NewStatsRepository(db, logger)
+	leaderboardRepo := repository.NewLeaderboardRepository(db, logger)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db, logger)
+	signingKeyRepo := repository.NewSigningKeyRepository(db, logger)
+
+	matchService := service.NewMatchService(db, logger, matchRepo, playerRepo, standingRepo, seasonRepo, ratingHistoryRepo)
+	tokenConfig := config.LoadTokenConfig(logger)
+	jwtConfig := config.LoadJWTConfig(logger, tokenConfig)
+	jwtKeys := jwtkeys.NewManager(jwtConfig.Issuer, jwtConfig.Audience)
+	signingKeyService := service.NewSigningKeyService(db, logger, jwtConfig, jwtKeys, signingKeyRepo)
+	if err := signingKeyService.Sync(); err != nil {
+		logger.Error("ошибка загрузки ключей подписи токенов", "error", err)
+		log.Fatal("Ошибка загрузки ключей подписи токенов:", err)
+	}
 
-	matchService := service.NewMatchService(db, logger, matchRepo, playerRepo, standingRepo)
-	playerService := service.NewPlayerService(db, logger, playerRepo, matchRepo)
-	seasonService := service.NewSeasonService(seasonRepo, logger)
+	authService := service.NewAuthService(db, logger, tokenConfig, jwtKeys, playerRepo, refreshTokenRepo)
+	rateLimitConfig := config.LoadRateLimitConfig(logger)
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if rateLimitConfig.Store == config.RateLimitStorePostgres {
+		rateLimitStore = repository.NewRateLimitRepository(db, logger)
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore, logger)
+
+	mailConfig := config.LoadMailConfig(logger)
+	mail, err := mailer.New(mailConfig, logger)
+	if err != nil {
+		logger.Error("ошибка настройки почты", "error", err)
+		log.Fatal("Ошибка настройки почты:", err)
+	}
+	accountService := service.NewAccountService(db, logger, config.LoadAccountTokenConfig(logger), mailConfig.BaseURL, mail, playerRepo, authService, limiter, rateLimitConfig.AccountPerEmail)
+	playerService := service.NewPlayerService(db, logger, playerRepo, matchRepo, authService, accountService, limiter, rateLimitConfig)
+	playerService.GrantAdmins(config.LoadAdminPlayerIDs(logger))
+	seasonService := service.NewSeasonService(db, seasonRepo, standingRepo, matchRepo, seasonResultRepo, matchService, logger)
 	standingService := service.NewStandingService(standingRepo, logger)
+	ratingService := service.NewRatingService(logger, playerRepo, ratingHistoryRepo)
+	statsService := service.NewStatsService(logger, playerRepo, matchRepo, statsRepo)
+	predictionService := service.NewPredictionService(logger, playerRepo, seasonRepo, standingRepo, statsRepo)
+	leaderboardService := service.NewLeaderboardService(logger, leaderboardRepo)
+	recomputeService := service.NewRecomputeService(db, logger, matchRepo, playerRepo, standingRepo, seasonRepo, ratingHistoryRepo)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	matchConfirmer := worker.NewMatchConfirmer(matchService, logger, config.LoadMatchConfirmationConfig(logger))
+	go matchConfirmer.Run(ctx)
+
+	seasonScheduler := worker.NewSeasonScheduler(seasonService, logger, config.LoadSeasonSchedulerConfig(logger))
+	go seasonScheduler.Run(ctx)
+
+	rateLimitSweeper := worker.NewRateLimitSweeper(limiter, logger, rateLimitConfig.SweepInterval)
+	go rateLimitSweeper.Run(ctx)
+
+	signingKeyRotator := worker.NewSigningKeyRotator(signingKeyService, logger, jwtConfig.CheckInterval)
+	go signingKeyRotator.Run(ctx)
 
 	r := gin.Default()
+	// Лимиты и блокировки входа считаются по c.ClientIP(): X-Forwarded-For принимается только от своих прокси
+	if err := r.SetTrustedProxies(rateLimitConfig.TrustedProxies); err != nil {
+		logger.Error("некорректный TRUSTED_PROXIES", "error", err)
+		log.Fatal("Некорректный TRUSTED_PROXIES:", err)
+	}
 
 	transport.RegisterRoutes(
-		r, matchService, playerService, seasonService, standingService, logger,
+		r, matchService, playerService, authService, accountService, seasonService, standingService, ratingService, statsService, predictionService, leaderboardService, recomputeService, seasonScheduler, signingKeyService, jwtKeys, limiter, rateLimitConfig, logger,
 	)
 
-	logger.Info("Server running on :8080")
-
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	if err := r.Run(); err != nil {
-		logger.Error("ошибка запуска сервера", "error", err)
-		log.Fatal("Ошибка запуска сервера:", err)
+	srv := &http.Server{Addr: ":8080", Handler: r}
+
+	go func() {
+		logger.Info("Server running on :8080")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("ошибка запуска сервера", "error", err)
+			log.Fatal("Ошибка запуска сервера:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	// Повторный сигнал завершает процесс сразу, не дожидаясь активных запросов
+	stop()
+	logger.Info("получен сигнал остановки, сервер завершает активные запросы")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("ошибка остановки сервера", "error", err)
+		return
 	}
+
+	logger.Info("сервер остановлен")
 }
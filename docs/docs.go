@@ -19,20 +19,48 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/matches": {
+        "/.well-known/jwks.json": {
             "get": {
-                "description": "Получить список матчей с фильтрами",
-                "consumes": [
+                "description": "Ключи (JWK Set, RFC 7517) для проверки access-токенов другими сервисами: текущий ключ подписи,\nследующий ключ (публикуется заранее, до начала подписи) и выведенные ключи, токены которых ещё действуют. Ключ выбирается по kid из заголовка токена;\nпри незнакомом kid набор нужно перезапросить — ключ мог смениться после ротации.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Открытые ключи подписи токенов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/shumnaya_internal_utils_jwtkeys.JWKSet"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/calibration": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Проверка прогнозов, сохранённых в подтверждённых матчах, на их исходах: Brier score (0 — идеально, 0.25 — монетка),\nулучшение относительно прогноза 50/50, log-loss и калибровка по корзинам прогноза.\nПрогноз берётся для стороны с меньшим ID игрока. Неявки и матчи без прогноза не учитываются.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Matches"
+                    "Admin"
                 ],
-                "summary": "Список матчей",
+                "summary": "Калибровка прогнозов",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Число корзин прогноза (по умолчанию 10, максимум 50)",
+                        "name": "buckets",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "ID сезона",
@@ -40,22 +68,37 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "ID игрока",
-                        "name": "player_id",
+                        "enum": [
+                            "elo",
+                            "glicko2",
+                            "trueskill"
+                        ],
+                        "type": "string",
+                        "description": "Рейтинговая система",
+                        "name": "engine",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "singles",
+                            "doubles"
+                        ],
+                        "type": "string",
+                        "description": "Только одиночные или только парные матчи",
+                        "name": "type",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "25.12.24",
-                        "description": "Дата начала (ДД.ММ.ГГ)",
+                        "example": "2025-01-01",
+                        "description": "Начало периода (RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ)",
                         "name": "from",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "31.12.24",
-                        "description": "Дата конца (ДД.ММ.ГГ)",
+                        "example": "2025-12-31",
+                        "description": "Конец периода (RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ)",
                         "name": "to",
                         "in": "query"
                     }
@@ -64,8 +107,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.CalibrationReport"
                         }
                     },
                     "400": {
@@ -77,6 +119,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -87,39 +147,44 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/jwt/rotate": {
             "post": {
-                "description": "Записать результат матча",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Немедленно выпускает новый ключ подписи и публикует его в JWKS; подписывать он начинает\nчерез JWT_KEY_PUBLISH_DELAY. После этого прежний ключ перестаёт подписывать,\nно проверяет уже выданные токены до конца льготного периода. Если следующий ключ уже\nвыпущен и ждёт начала подписи, возвращается он.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Matches"
+                    "Admin"
                 ],
-                "summary": "Создать матч",
-                "parameters": [
-                    {
-                        "description": "Параметры матча",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                "summary": "Ротировать ключ подписи токенов",
+                "responses": {
+                    "200": {
+                        "description": "kid нового ключа",
                         "schema": {
-                            "$ref": "#/definitions/models.CreateMatchRequest"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.Match"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -139,9 +204,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/players": {
-            "post": {
-                "description": "Создает нового игрока",
+        "/admin/players/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Роли: player — свои матчи, organizer — свои сезоны и их матчи, admin — всё.\nНовая роль действует со следующего входа игрока.",
                 "consumes": [
                     "application/json"
                 ],
@@ -149,26 +219,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Admin"
                 ],
-                "summary": "Регистрация игрока",
+                "summary": "Назначить роль игроку",
                 "parameters": [
                     {
-                        "description": "Данные регистрации",
+                        "type": "integer",
+                        "description": "ID игрока",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Роль",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/transport.RegisterPlayerRequest"
+                            "$ref": "#/definitions/dto.SetRoleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/transport.RegisterPlayerResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -178,33 +252,86 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/players/{id}": {
-            "get": {
+        "/admin/recompute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заново проводит все подтверждённые матчи по текущим правилам рейтинга: пересобирает рейтинги игроков, изменения рейтинга в матчах, историю рейтинга и турнирные таблицы. В режиме dry_run ничего не сохраняет и только возвращает разницу по игрокам.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Players"
+                    "Admin"
                 ],
-                "summary": "Профиль игрока",
+                "summary": "Пересчитать рейтинги по всей истории",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Только посчитать разницу, ничего не сохраняя",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID игрока",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Размер пачки матчей (по умолчанию 5000, максимум 10000)",
+                        "name": "batch_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько игроков с наибольшим изменением вернуть (по умолчанию 100, 0 — всех)",
+                        "name": "top",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PlayerProfile"
+                            "$ref": "#/definitions/models.RecomputeReport"
                         }
                     },
                     "400": {
@@ -216,6 +343,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -228,27 +373,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/seasons": {
+        "/admin/season-scheduler": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Настройки планировщика и его последние действия (закрытие, активация и создание сезонов), новые первыми",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Seasons"
+                    "Admin"
                 ],
-                "summary": "Все сезоны",
+                "summary": "Журнал планировщика сезонов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Season"
+                            "$ref": "#/definitions/models.SchedulerStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -257,8 +414,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/logout": {
             "post": {
+                "description": "Завершает сессию, к которой относится refresh-токен. Выданный access-токен действует до истечения своего срока.",
                 "consumes": [
                     "application/json"
                 ],
@@ -266,26 +426,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Seasons"
+                    "Auth"
                 ],
-                "summary": "Создать сезон",
+                "summary": "Выйти",
                 "parameters": [
                     {
-                        "description": "Сезон",
+                        "description": "Refresh-токен",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Season"
+                            "$ref": "#/definitions/dto.RefreshRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.Season"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -295,37 +452,9 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/seasons/{id}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Seasons"
-                ],
-                "summary": "Сезон по ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID сезона",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Season"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -333,8 +462,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -345,36 +474,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/seasons/{id}/standings": {
-            "get": {
+        "/auth/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершает все сессии игрока. Выданные access-токены действуют до истечения своего срока.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Seasons"
-                ],
-                "summary": "Таблица сезона",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID сезона",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Auth"
                 ],
+                "summary": "Выйти на всех устройствах",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Standing"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -382,8 +502,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -393,121 +513,3183 @@ const docTemplate = `{
                     }
                 }
             }
-        }
+        },
+        "/auth/password-reset": {
+            "post": {
+                "description": "Принимает токен из письма и новый пароль. Ссылка одноразовая; все сессии игрока завершаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Задать новый пароль",
+                "parameters": [
+                    {
+                        "description": "Токен из письма и новый пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasswordResetConfirmRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset/request": {
+            "post": {
+                "description": "Отправляет ссылку сброса пароля, если игрок с таким email зарегистрирован.\nОтвет не зависит от того, есть ли такой игрок.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Запросить сброс пароля",
+                "parameters": [
+                    {
+                        "description": "Email",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много писем; см. Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Обменивает refresh-токен на новую пару токенов. Каждый refresh-токен действует один раз;\nповторное использование уже обменянного токена отзывает всю сессию.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Обновить токены",
+                "parameters": [
+                    {
+                        "description": "Refresh-токен",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "Принимает токен из ссылки в письме. Ссылка одноразовая.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Подтвердить email",
+                "parameters": [
+                    {
+                        "description": "Токен из письма",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email/request": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отправляет на email игрока новую ссылку подтверждения. Без подтверждения нельзя записывать матчи.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Повторно отправить письмо подтверждения email",
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много писем; см. Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard": {
+            "get": {
+                "description": "Игроки по убыванию рейтинга; игроки с равным рейтингом делят место. Без season_id — общий рейтинг,\nс season_id — сезонный рейтинг разряда type (по умолчанию одиночного) среди сыгравших в сезоне.\nИгроки с числом матчей меньше 10 помечаются как provisional. Если передан токен, строка игрока\nпомечается is_me, а его место при тех же фильтрах возвращается в me.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Рейтинг-лист игроков",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сезона",
+                        "name": "season_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "singles",
+                            "doubles"
+                        ],
+                        "type": "string",
+                        "description": "Разряд матчей",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Не меньше стольких подтверждённых матчей",
+                        "name": "min_games",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Последний матч не раньше стольких дней назад",
+                        "name": "active_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Посчитать общее число игроков по фильтру",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LeaderboardPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Возвращает короткоживущий access-токен и refresh-токен для POST /auth/refresh.\nПосле серии неудачных попыток вход по email (и с IP) блокируется, каждая следующая неудача удваивает блокировку.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Вход",
+                "parameters": [
+                    {
+                        "description": "Email и пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Лимит попыток или блокировка; см. Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/matches": {
+            "get": {
+                "description": "Получить страницу матчей с фильтрами. Матчи упорядочены по (played_at, id);\nследующая страница запрашивается с cursor из next_cursor предыдущей и той же сортировкой.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Matches"
+                ],
+                "summary": "Список матчей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сезона",
+                        "name": "season_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID игрока",
+                        "name": "player_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "confirmed",
+                            "disputed"
+                        ],
+                        "type": "string",
+                        "description": "Статус матча",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "singles",
+                            "doubles"
+                        ],
+                        "type": "string",
+                        "description": "Разряд матча",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID соперника игрока player_id (с любой стороны пары)",
+                        "name": "opponent_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "won",
+                            "lost"
+                        ],
+                        "type": "string",
+                        "description": "Исход для игрока player_id",
+                        "name": "result",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "3:0",
+                        "description": "Счёт по партиям, победитель первым, например 3:0",
+                        "name": "score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная разница рейтингов победителя и проигравшего перед матчем",
+                        "name": "min_rating_diff",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная разница рейтингов победителя и проигравшего перед матчем",
+                        "name": "max_rating_diff",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только победы более слабой по рейтингу стороны",
+                        "name": "upsets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Место проведения (без учёта регистра)",
+                        "name": "venue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-12-25",
+                        "description": "Дата начала: RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-12-31",
+                        "description": "Дата конца: RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ (дата без времени — включая весь день)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "-played_at",
+                            "played_at"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка: -played_at (по умолчанию, новые первыми) или played_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Посчитать общее число матчей по фильтру",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MatchPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Записать результат матча. Счёт передаётся по партиям (\"11-9 7-11 11-5 11-8\"),\nпервым в каждой партии идёт счёт победителя. Формат (best-of-N, до 11/21) берётся из сезона.\nДля парного матча указываются winner_partner_id и loser_partner_id.\nЗаявить результат может только участник матча; матч ждёт подтверждения соперника\nи до этого не влияет на рейтинги.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Matches"
+                ],
+                "summary": "Создать матч",
+                "parameters": [
+                    {
+                        "description": "Параметры матча",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateMatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Match"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/matches/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Исправляет результат матча и пересчитывает рейтинги и таблицы по всем последующим матчам.\nДоступно администраторам и организатору сезона матча.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Matches"
+                ],
+                "summary": "Исправить матч",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID матча",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Исправленные параметры матча",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Match"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет матч и пересчитывает рейтинги и таблицы по всем последующим матчам.\nДоступно администраторам и организатору сезона матча.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Matches"
+                ],
+                "summary": "Аннулировать матч",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID матча",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/matches/{id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Соперник заявившего игрока подтверждает результат, после чего матч учитывается в рейтингах и таблице",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Matches"
+                ],
+                "summary": "Подтвердить матч",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID матча",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Match"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/matches/{id}/dispute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Соперник заявившего игрока отклоняет результат; матч не учитывается, пока его не исправят",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Matches"
+                ],
+                "summary": "Оспорить матч",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID матча",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DisputeMatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Match"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/players": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Поиск игроков для выбора соперника: q ищет по началу имени и по похожему имени (триграммы).\nВозвращаются только публичные сведения — без email.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Список игроков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя или его начало",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "-name",
+                            "-rating",
+                            "rating",
+                            "-last_active",
+                            "last_active"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка: name (по умолчанию), -name, -rating, rating, -last_active (недавно игравшие первыми), last_active",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Посчитать общее число игроков по запросу",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlayerSummaryPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает нового игрока",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Регистрация игрока",
+                "parameters": [
+                    {
+                        "description": "Данные регистрации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterPlayerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много регистраций; см. Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/players/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Публичный профиль (dto.PublicPlayerProfile) доступен любому авторизованному игроку.\nСвой профиль игрок получает в расширенном виде (dto.PrivatePlayerProfile) с данными учётной записи.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Профиль игрока",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID игрока",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "singles",
+                            "doubles"
+                        ],
+                        "type": "string",
+                        "description": "Только одиночные или только парные матчи",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Свой профиль; для чужого — dto.PublicPlayerProfile без account",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PrivatePlayerProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/players/{id}/head-to-head/{opponentId}": {
+            "get": {
+                "description": "Встречи игрока и соперника на разных сторонах подтверждённых матчей (в том числе в составе пар):\nсчёт, партии и очки, суммарное изменение рейтинга каждого, серии внутри противостояния,\nразбивка по сезонам, последние встречи и прогноз следующей встречи по Эло.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Личные встречи игроков",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID игрока (A)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID соперника (B)",
+                        "name": "opponentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "singles",
+                            "doubles"
+                        ],
+                        "type": "string",
+                        "description": "Разряд: singles (по умолчанию) или doubles",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько последних встреч вернуть (по умолчанию 5, максимум 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeadToHeadRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/players/{id}/rating-history": {
+            "get": {
+                "description": "Временной ряд рейтинга по подтверждённым матчам с пиком и минимумом за период",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "История рейтинга игрока",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID игрока",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-01-01",
+                        "description": "Начало периода (RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-12-31",
+                        "description": "Конец периода (RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID сезона",
+                        "name": "season_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RatingTimeline"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/players/{id}/stats": {
+            "get": {
+                "description": "Статистика по подтверждённым матчам: общий счёт, доли выигранных партий и очков, текущая и самые длинные серии,\nформа в последних 10 матчах, результаты против соперников сильнее и слабее по рейтингу перед матчем,\nразбивка по сезонам, любимые и неудобные соперники.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Статистика игрока",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID игрока",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "singles",
+                            "doubles"
+                        ],
+                        "type": "string",
+                        "description": "Только одиночные или только парные матчи",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlayerStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/players/{id}/vs/{opponentId}/{limit}": {
+            "get": {
+                "description": "Используйте GET /players/{id}/head-to-head/{opponentId}?limit=",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Личные встречи игроков (устаревший адрес)",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID игрока (A)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID соперника (B)",
+                        "name": "opponentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько последних встреч вернуть",
+                        "name": "limit",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeadToHeadRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/predict": {
+            "get": {
+                "description": "Вероятность победы каждого игрока в одиночном матче и изменение их общего рейтинга при каждом исходе.\nСчитается рейтинговой системой сезона (по умолчанию активного, без активного сезона — Elo) так же,\nкак при записи результата.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Predictions"
+                ],
+                "summary": "Прогноз матча",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID игрока A",
+                        "name": "player_a",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID игрока B",
+                        "name": "player_b",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID сезона, по правилам которого считать прогноз",
+                        "name": "season_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MatchPrediction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons": {
+            "get": {
+                "description": "Страница сезонов, упорядоченных по (start_date, id)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Сезоны",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "-start_date",
+                            "start_date"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка: -start_date (по умолчанию, новые первыми) или start_date",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Посчитать общее число сезонов",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeasonPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сезон создаётся неактивным; активируется через POST /seasons/{id}/activate.\nСоздают сезоны организаторы и администраторы. Организатор становится организатором сезона,\nадминистратор может назначить организатора через organizer_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Создать сезон",
+                "parameters": [
+                    {
+                        "description": "Сезон",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Season"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Season"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Сезон по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сезона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Season"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}/activate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Активным может быть только один сезон; закрытый или закончившийся сезон активировать нельзя.\nДоступно администраторам и организатору сезона.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Активировать сезон",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сезона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Season"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}/close": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Фиксирует итоговые места, снимок таблиц и награды. После закрытия матчи сезона изменить нельзя.\nДоступно администраторам и организатору сезона.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Закрыть сезон",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сезона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeasonSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}/results": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Итоги закрытого сезона",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сезона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeasonSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}/standings": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Таблица сезона",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сезона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "singles",
+                            "doubles"
+                        ],
+                        "type": "string",
+                        "description": "Разряд: singles (по умолчанию) или doubles",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "points",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Порядок: points (по умолчанию) — по местам с учётом критериев сезона, rating — по сезонному рейтингу",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor), действителен для того же order_by",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Посчитать общее число строк таблицы",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StandingPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
     },
     "definitions": {
-        "gorm.DeletedAt": {
+        "dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "секунд до истечения access-токена",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PasswordResetConfirmRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "secret123"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PasswordResetRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "test@mail.com"
+                }
+            }
+        },
+        "dto.PlayerAccount": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "test@mail.com"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "rating_deviation": {
+                    "description": "Неопределённость рейтинга (Glicko-2, TrueSkill) — служебные сведения, в публичный профиль не входят",
+                    "type": "number"
+                },
+                "rating_volatility": {
+                    "type": "number"
+                },
+                "registered_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "player"
+                }
+            }
+        },
+        "dto.PrivatePlayerProfile": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/dto.PlayerAccount"
+                },
+                "doubles": {
+                    "$ref": "#/definitions/models.MatchSummary"
+                },
+                "losses": {
+                    "type": "integer"
+                },
+                "player": {
+                    "$ref": "#/definitions/dto.PublicPlayer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "recent_matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Match"
+                    }
+                },
+                "singles": {
+                    "$ref": "#/definitions/models.MatchSummary"
+                },
+                "total_matches": {
+                    "type": "integer"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PublicPlayer": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RegisterPlayerRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "test@mail.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Иван"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "secret123"
+                }
+            }
+        },
+        "dto.SetRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "player",
+                        "organizer",
+                        "admin"
+                    ],
+                    "example": "organizer"
+                }
+            }
+        },
+        "dto.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CalibrationBucket": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "number"
+                },
+                "matches": {
+                    "type": "integer"
+                },
+                "mean_predicted": {
+                    "type": "number"
+                },
+                "observed_rate": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CalibrationReport": {
+            "type": "object",
+            "properties": {
+                "brier_score": {
+                    "description": "BrierScore — средний квадрат ошибки прогноза (0 — идеально, 0.25 — монетка),\nBrierSkill — улучшение относительно прогноза 50/50, LogLoss — средняя логарифмическая ошибка.",
+                    "type": "number"
+                },
+                "brier_skill": {
+                    "type": "number"
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CalibrationBucket"
+                    }
+                },
+                "log_loss": {
+                    "type": "number"
+                },
+                "matches": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateMatchRequest": {
+            "type": "object",
+            "required": [
+                "loser_id",
+                "season_id",
+                "winner_id"
+            ],
+            "properties": {
+                "forfeit": {
+                    "description": "проигравший не явился, счёт не нужен",
+                    "type": "boolean"
+                },
+                "loser_id": {
+                    "type": "integer"
+                },
+                "loser_partner_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "score": {
+                    "description": "очки по партиям, первым идёт победитель",
+                    "type": "string",
+                    "example": "11-9 7-11 11-5 11-8"
+                },
+                "season_id": {
+                    "type": "integer"
+                },
+                "venue": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Зал №2"
+                },
+                "winner_id": {
+                    "type": "integer"
+                },
+                "winner_partner_id": {
+                    "description": "Для парного матча передаются оба партнёра",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "models.DisputeMatchRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "счёт последней партии был 9-11"
+                }
+            }
+        },
+        "models.HeadToHeadPrediction": {
+            "type": "object",
+            "properties": {
+                "player_a_rating": {
+                    "type": "integer"
+                },
+                "player_a_win_probability": {
+                    "type": "number"
+                },
+                "player_b_rating": {
+                    "type": "integer"
+                },
+                "player_b_win_probability": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.HeadToHeadRecord": {
+            "type": "object",
+            "properties": {
+                "last_matches_played": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Match"
+                    }
+                },
+                "player_a_games": {
+                    "description": "Выигранные партии и очки по счёту матчей",
+                    "type": "integer"
+                },
+                "player_a_id": {
+                    "type": "integer"
+                },
+                "player_a_points": {
+                    "type": "integer"
+                },
+                "player_a_rating_change": {
+                    "description": "Сумма изменений рейтинга каждого игрока в этих матчах",
+                    "type": "integer"
+                },
+                "player_a_wins": {
+                    "type": "integer"
+                },
+                "player_b_games": {
+                    "type": "integer"
+                },
+                "player_b_id": {
+                    "type": "integer"
+                },
+                "player_b_points": {
+                    "type": "integer"
+                },
+                "player_b_rating_change": {
+                    "type": "integer"
+                },
+                "player_b_wins": {
+                    "type": "integer"
+                },
+                "prediction": {
+                    "$ref": "#/definitions/models.HeadToHeadPrediction"
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HeadToHeadSeason"
+                    }
+                },
+                "streaks": {
+                    "$ref": "#/definitions/models.HeadToHeadStreaks"
+                },
+                "total_matches": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HeadToHeadSeason": {
+            "type": "object",
+            "properties": {
+                "player_a_wins": {
+                    "type": "integer"
+                },
+                "player_b_wins": {
+                    "type": "integer"
+                },
+                "season_id": {
+                    "type": "integer"
+                },
+                "season_name": {
+                    "type": "string"
+                },
+                "total_matches": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HeadToHeadStreaks": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "integer"
+                },
+                "longest_a": {
+                    "type": "integer"
+                },
+                "longest_b": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "games": {
+                    "type": "integer"
+                },
+                "is_me": {
+                    "description": "IsMe — строка авторизованного игрока, запросившего лист",
+                    "type": "boolean"
+                },
+                "last_match_at": {
+                    "type": "string"
+                },
+                "losses": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "player_id": {
+                    "type": "integer"
+                },
+                "provisional": {
+                    "description": "Provisional — у игрока меньше elo.ProvisionalGames матчей, рейтинг ещё не устоялся",
+                    "type": "boolean"
+                },
+                "rank": {
+                    "description": "игроки с равным рейтингом делят место",
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LeaderboardPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LeaderboardEntry"
+                    }
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "me": {
+                    "$ref": "#/definitions/models.LeaderboardEntry"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Match": {
+            "type": "object",
+            "required": [
+                "loser_id",
+                "score",
+                "season_id",
+                "winner_id"
+            ],
+            "properties": {
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "dispute_reason": {
+                    "type": "string"
+                },
+                "forfeit": {
+                    "description": "Неявка: матч засчитывается в таблицу по очкам за неявку, счёта нет, рейтинг не меняется",
+                    "type": "boolean"
+                },
+                "games": {
+                    "description": "счёт по партиям",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MatchGame"
+                    }
+                },
+                "id": {
+                    "description": "по id матч подтверждают, оспаривают, исправляют и удаляют",
+                    "type": "integer"
+                },
+                "loser_games": {
+                    "type": "integer"
+                },
+                "loser_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "loser_partner_id": {
+                    "type": "integer"
+                },
+                "loser_partner_rating_change": {
+                    "type": "integer"
+                },
+                "loser_rating_before": {
+                    "type": "integer"
+                },
+                "loser_rating_change": {
+                    "type": "integer"
+                },
+                "loser_standing_points": {
+                    "type": "integer"
+                },
+                "played_at": {
+                    "type": "string"
+                },
+                "rating_engine": {
+                    "description": "Какая система посчитала рейтинг и какую вероятность победы она давала победителю до матча",
+                    "type": "string"
+                },
+                "reported_by_id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "string"
+                },
+                "season_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "status": {
+                    "description": "Подтверждение результата соперником. Матчи до появления подтверждений считаются подтверждёнными.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "venue": {
+                    "description": "где играли",
+                    "type": "string"
+                },
+                "winner_games": {
+                    "type": "integer"
+                },
+                "winner_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "winner_partner_id": {
+                    "description": "Партнёры в парном матче. WinnerID/LoserID — первые игроки каждой пары.",
+                    "type": "integer"
+                },
+                "winner_partner_rating_change": {
+                    "type": "integer"
+                },
+                "winner_rating_before": {
+                    "description": "Рейтинги сторон перед матчем (для пары — по правилу сезона DoublesTeamRating).\nПусты у матчей, проведённых до появления полей, пока история не пересчитана.",
+                    "type": "integer"
+                },
+                "winner_rating_change": {
+                    "type": "integer"
+                },
+                "winner_standing_points": {
+                    "description": "Очки в таблицу, начисленные каждому игроку стороны по схеме сезона. Матчи, проведённые\nдо появления схемы, давали победителю 1 очко (см. repository.PrepareMigrations).",
+                    "type": "integer"
+                },
+                "winner_win_probability": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.MatchGame": {
+            "type": "object",
+            "properties": {
+                "loser_points": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "winner_points": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MatchPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Match"
+                    }
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MatchPrediction": {
+            "type": "object",
+            "properties": {
+                "engine": {
+                    "type": "string"
+                },
+                "if_player_a_wins": {
+                    "description": "Изменение общего рейтинга игроков при каждом исходе",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RatingSwing"
+                        }
+                    ]
+                },
+                "if_player_b_wins": {
+                    "$ref": "#/definitions/models.RatingSwing"
+                },
+                "player_a_id": {
+                    "type": "integer"
+                },
+                "player_a_rating": {
+                    "type": "integer"
+                },
+                "player_a_win_probability": {
+                    "type": "number"
+                },
+                "player_b_id": {
+                    "type": "integer"
+                },
+                "player_b_rating": {
+                    "type": "integer"
+                },
+                "player_b_win_probability": {
+                    "type": "number"
+                },
+                "season_id": {
+                    "description": "сезон, по правилам которого посчитан прогноз",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MatchSummary": {
+            "type": "object",
+            "properties": {
+                "losses": {
+                    "type": "integer"
+                },
+                "total_matches": {
+                    "type": "integer"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OpponentRecord": {
+            "type": "object",
+            "properties": {
+                "losses": {
+                    "type": "integer"
+                },
+                "matches": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opponent_id": {
+                    "type": "integer"
+                },
+                "win_rate": {
+                    "type": "number"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Player": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "matches": {
+                    "description": "история матчей по игроку (поле для удобства, запросы через репозиторий)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Match"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "rating_deviation": {
+                    "description": "Неопределённость рейтинга для Glicko-2 (RD и волатильность) и TrueSkill (σ)",
+                    "type": "number"
+                },
+                "rating_volatility": {
+                    "type": "number"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlayerForm": {
+            "type": "object",
+            "properties": {
+                "losses": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "string",
+                    "example": "WWLWWLLWWW"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlayerRatingDiff": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "new_rating": {
+                    "type": "integer"
+                },
+                "old_rating": {
+                    "type": "integer"
+                },
+                "player_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlayerStats": {
+            "type": "object",
+            "properties": {
+                "favourites": {
+                    "description": "Соперники, которых игрок обыгрывает чаще всего, и те, кому он чаще всего проигрывает",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OpponentRecord"
+                    }
+                },
+                "form": {
+                    "$ref": "#/definitions/models.PlayerForm"
+                },
+                "nemeses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OpponentRecord"
+                    }
+                },
+                "player_id": {
+                    "type": "integer"
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SeasonSplit"
+                    }
+                },
+                "streaks": {
+                    "$ref": "#/definitions/models.PlayerStreaks"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.PlayerStatsSummary"
+                },
+                "type": {
+                    "description": "пусто — матчи любого разряда",
+                    "type": "string"
+                },
+                "vs_higher_rated": {
+                    "description": "Победы над соперниками сильнее и слабее по рейтингу перед матчем (без неявок\nи матчей без сохранённого рейтинга сторон)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RecordSplit"
+                        }
+                    ]
+                },
+                "vs_lower_rated": {
+                    "$ref": "#/definitions/models.RecordSplit"
+                }
+            }
+        },
+        "models.PlayerStatsSummary": {
+            "type": "object",
+            "properties": {
+                "games_lost": {
+                    "type": "integer"
+                },
+                "games_won": {
+                    "description": "Партии и очки по счёту матчей",
+                    "type": "integer"
+                },
+                "games_won_ratio": {
+                    "type": "number"
+                },
+                "losses": {
+                    "type": "integer"
+                },
+                "matches": {
+                    "type": "integer"
+                },
+                "points_lost": {
+                    "type": "integer"
+                },
+                "points_won": {
+                    "type": "integer"
+                },
+                "points_won_ratio": {
+                    "type": "number"
+                },
+                "win_rate": {
+                    "type": "number"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlayerStreaks": {
             "type": "object",
             "properties": {
-                "time": {
+                "current": {
+                    "type": "integer"
+                },
+                "longest_loss": {
+                    "type": "integer"
+                },
+                "longest_win": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlayerSummary": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "last_match_at": {
+                    "description": "последний подтверждённый матч",
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
                 },
-                "valid": {
-                    "description": "Valid is true if Time is not NULL",
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlayerSummaryPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlayerSummary"
+                    }
+                },
+                "has_more": {
                     "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateMatchRequest": {
+        "models.PointsScheme": {
             "type": "object",
-            "required": [
-                "loser_id",
-                "score",
-                "season_id",
-                "winner_id"
-            ],
             "properties": {
-                "loser_id": {
+                "forfeit_loss": {
+                    "type": "integer",
+                    "example": -1
+                },
+                "forfeit_win": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "loss": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "participation": {
                     "type": "integer"
                 },
-                "score": {
+                "sweep_bonus": {
+                    "description": "победа всухую",
+                    "type": "integer"
+                },
+                "upset_bonus": {
+                    "description": "победа над фаворитом (прогноз на победу ниже 50%)",
+                    "type": "integer"
+                },
+                "win": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.RatingExtreme": {
+            "type": "object",
+            "properties": {
+                "match_id": {
+                    "type": "integer"
+                },
+                "played_at": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RatingPoint": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "integer"
+                },
+                "engine": {
                     "type": "string"
                 },
+                "match_id": {
+                    "type": "integer"
+                },
+                "played_at": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "rating_before": {
+                    "type": "integer"
+                },
                 "season_id": {
                     "type": "integer"
                 },
-                "winner_id": {
+                "season_rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RatingSwing": {
+            "type": "object",
+            "properties": {
+                "player_a_change": {
+                    "type": "integer"
+                },
+                "player_b_change": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RatingTimeline": {
+            "type": "object",
+            "properties": {
+                "current_rating": {
+                    "type": "integer"
+                },
+                "lowest": {
+                    "$ref": "#/definitions/models.RatingExtreme"
+                },
+                "peak": {
+                    "$ref": "#/definitions/models.RatingExtreme"
+                },
+                "player_id": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RatingPoint"
+                    }
+                }
+            }
+        },
+        "models.RecomputeReport": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duration": {
+                    "type": "string"
+                },
+                "matches": {
+                    "type": "integer"
+                },
+                "matches_changed": {
+                    "type": "integer"
+                },
+                "players": {
+                    "description": "Игроки, чей рейтинг изменился, по убыванию модуля изменения",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlayerRatingDiff"
+                    }
+                },
+                "players_changed": {
+                    "type": "integer"
+                },
+                "standings": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecordSplit": {
+            "type": "object",
+            "properties": {
+                "losses": {
+                    "type": "integer"
+                },
+                "matches": {
+                    "type": "integer"
+                },
+                "win_rate": {
+                    "type": "number"
+                },
+                "wins": {
                     "type": "integer"
                 }
             }
         },
-        "models.Match": {
+        "models.SchedulerAction": {
             "type": "object",
-            "required": [
-                "loser_id",
-                "score",
-                "season_id",
-                "winner_id"
-            ],
             "properties": {
-                "createdAt": {
+                "action": {
                     "type": "string"
                 },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "loser": {
-                    "$ref": "#/definitions/models.Player"
-                },
-                "loser_id": {
-                    "type": "integer",
-                    "minimum": 1
-                },
-                "loser_rating_change": {
-                    "type": "integer"
-                },
-                "played_at": {
+                "at": {
                     "type": "string"
                 },
-                "score": {
+                "error": {
+                    "description": "действие не удалось и будет повторено; та же ошибка повторно не записывается",
                     "type": "string"
                 },
-                "season": {
-                    "$ref": "#/definitions/models.Season"
-                },
                 "season_id": {
-                    "type": "integer",
-                    "minimum": 1
+                    "type": "integer"
                 },
-                "updatedAt": {
+                "season_name": {
                     "type": "string"
+                }
+            }
+        },
+        "models.SchedulerStatus": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "description": "последние действия, новые первыми",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SchedulerAction"
+                    }
                 },
-                "winner": {
-                    "$ref": "#/definitions/models.Player"
+                "interval": {
+                    "type": "string"
                 },
-                "winner_id": {
-                    "type": "integer",
-                    "minimum": 1
+                "last_run_at": {
+                    "type": "string"
                 },
-                "winner_rating_change": {
-                    "type": "integer"
+                "recurrence": {
+                    "type": "string"
                 }
             }
         },
-        "models.Player": {
+        "models.Season": {
             "type": "object",
             "required": [
-                "email",
-                "name"
+                "end_date",
+                "name",
+                "start_date"
             ],
             "properties": {
-                "createdAt": {
+                "best_of": {
+                    "description": "Формат матчей сезона: best-of-N партий до GamePoints очков (с разницей в 2 очка)",
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        3,
+                        5,
+                        7
+                    ]
+                },
+                "closed_at": {
                     "type": "string"
                 },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "doubles_team_rating": {
+                    "description": "Как считается рейтинг пары в парных матчах: средний, по сильнейшему или по слабейшему игроку",
+                    "type": "string",
+                    "enum": [
+                        "average",
+                        "strongest",
+                        "weakest"
+                    ]
                 },
-                "email": {
+                "end_date": {
                     "type": "string"
                 },
+                "game_points": {
+                    "type": "integer",
+                    "enum": [
+                        11,
+                        21
+                    ]
+                },
                 "id": {
+                    "description": "по id сезон активируют и закрывают",
                     "type": "integer"
                 },
+                "is_active": {
+                    "description": "Активным может быть только один сезон; закрытый сезон (ClosedAt) больше не меняется",
+                    "type": "boolean"
+                },
                 "matches": {
-                    "description": "история матчей по игроку (поле для удобства, запросы через репозиторий)",
+                    "description": "получение матчей по сезонам",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Match"
@@ -516,82 +3698,180 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "password_hash": {
-                    "type": "string"
+                "organizer_id": {
+                    "description": "Организатор сезона: кроме администраторов, только он активирует и закрывает сезон\nи исправляет его матчи. У сезонов, созданных администратором или планировщиком, может быть пустым.",
+                    "type": "integer"
                 },
-                "rating": {
+                "points": {
+                    "description": "Сколько очков в таблицу приносит матч",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PointsScheme"
+                        }
+                    ]
+                },
+                "rating_engine": {
+                    "description": "Рейтинговая система сезона",
+                    "type": "string",
+                    "enum": [
+                        "elo",
+                        "glicko2",
+                        "trueskill"
+                    ]
+                },
+                "rating_regress_percent": {
                     "type": "integer",
+                    "maximum": 100,
                     "minimum": 0
                 },
-                "updatedAt": {
+                "rating_reset": {
+                    "description": "С чего игрок начинает сезонный рейтинг: с общего рейтинга (carry), со сдвига общего\nрейтинга к стартовому на RatingRegressPercent процентов (regress) или со стартового (hard)",
+                    "type": "string",
+                    "enum": [
+                        "carry",
+                        "regress",
+                        "hard"
+                    ]
+                },
+                "start_date": {
                     "type": "string"
+                },
+                "tie_breakers": {
+                    "description": "Критерии распределения мест через запятую, например \"points,head_to_head,games_ratio\"",
+                    "type": "string",
+                    "example": "points,win_diff,rating"
                 }
             }
         },
-        "models.PlayerProfile": {
+        "models.SeasonAward": {
             "type": "object",
             "properties": {
-                "losses": {
-                    "type": "integer"
+                "award": {
+                    "type": "string"
                 },
                 "player": {
                     "$ref": "#/definitions/models.Player"
                 },
-                "rating": {
+                "player_id": {
                     "type": "integer"
                 },
-                "recent_matches": {
+                "season_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "место, число побед/матчей или рейтинг",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SeasonPage": {
+            "type": "object",
+            "properties": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Match"
+                        "$ref": "#/definitions/models.Season"
                     }
                 },
-                "total_matches": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
                     "type": "integer"
                 },
-                "wins": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "models.Season": {
+        "models.SeasonResult": {
             "type": "object",
-            "required": [
-                "end_date",
-                "name",
-                "start_date"
-            ],
             "properties": {
-                "createdAt": {
-                    "type": "string"
+                "losses": {
+                    "type": "integer"
+                },
+                "player": {
+                    "$ref": "#/definitions/models.Player"
                 },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "player_id": {
+                    "type": "integer"
                 },
-                "end_date": {
+                "points": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "description": "сезонный рейтинг на момент закрытия",
+                    "type": "integer"
+                },
+                "season_id": {
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
                 },
-                "id": {
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SeasonSplit": {
+            "type": "object",
+            "properties": {
+                "games_lost": {
                     "type": "integer"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "games_won": {
+                    "type": "integer"
+                },
+                "losses": {
+                    "type": "integer"
                 },
                 "matches": {
-                    "description": "получение матчей по сезонам",
+                    "type": "integer"
+                },
+                "season_id": {
+                    "type": "integer"
+                },
+                "season_name": {
+                    "type": "string"
+                },
+                "win_rate": {
+                    "type": "number"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SeasonSummary": {
+            "type": "object",
+            "properties": {
+                "awards": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Match"
+                        "$ref": "#/definitions/models.SeasonAward"
                     }
                 },
-                "name": {
+                "closed_at": {
                     "type": "string"
                 },
-                "start_date": {
-                    "type": "string"
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SeasonResult"
+                    }
                 },
-                "updatedAt": {
-                    "type": "string"
+                "season_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -602,19 +3882,13 @@ const docTemplate = `{
                 "season_id"
             ],
             "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "deletedAt": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
-                },
-                "id": {
-                    "type": "integer"
-                },
                 "losses": {
                     "type": "integer",
                     "minimum": 0
                 },
+                "movement": {
+                    "type": "integer"
+                },
                 "player": {
                     "$ref": "#/definitions/models.Player"
                 },
@@ -626,10 +3900,25 @@ const docTemplate = `{
                     "type": "integer",
                     "minimum": 0
                 },
+                "previous_rank": {
+                    "description": "Место на конец прошлой недели и изменение с тех пор (положительное — подъём)",
+                    "type": "integer"
+                },
                 "rank": {
+                    "description": "место считается в базе по правилам сезона (см. Season.TieBreakers)",
                     "type": "integer",
                     "minimum": 0
                 },
+                "rating": {
+                    "description": "Сезонный рейтинг. Заводится при первом матче игрока в сезоне по политике сброса сезона\nи меняется только матчами этого сезона; общий рейтинг хранится в Player.Rating.",
+                    "type": "integer"
+                },
+                "rating_deviation": {
+                    "type": "number"
+                },
+                "rating_volatility": {
+                    "type": "number"
+                },
                 "season": {
                     "$ref": "#/definitions/models.Season"
                 },
@@ -637,7 +3926,8 @@ const docTemplate = `{
                     "type": "integer",
                     "minimum": 1
                 },
-                "updatedAt": {
+                "type": {
+                    "description": "Разряд таблицы: одиночки и пары считаются отдельно",
                     "type": "string"
                 },
                 "wins": {
@@ -646,38 +3936,119 @@ const docTemplate = `{
                 }
             }
         },
-        "transport.RegisterPlayerRequest": {
+        "models.StandingPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Standing"
+                    }
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UpdateMatchRequest": {
             "type": "object",
             "required": [
-                "email",
-                "name",
-                "password"
+                "loser_id",
+                "season_id",
+                "winner_id"
             ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "test@mail.com"
+                "forfeit": {
+                    "type": "boolean"
                 },
-                "name": {
+                "loser_id": {
+                    "type": "integer"
+                },
+                "loser_partner_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "score": {
                     "type": "string",
-                    "example": "Иван"
+                    "example": "11-9 7-11 11-5 11-8"
                 },
-                "password": {
+                "season_id": {
+                    "type": "integer"
+                },
+                "venue": {
                     "type": "string",
-                    "minLength": 6,
-                    "example": "secret123"
+                    "maxLength": 255,
+                    "example": "Зал №2"
+                },
+                "winner_id": {
+                    "type": "integer"
+                },
+                "winner_partner_id": {
+                    "description": "Для парного матча передаются оба партнёра",
+                    "type": "integer",
+                    "minimum": 1
                 }
             }
         },
-        "transport.RegisterPlayerResponse": {
+        "shumnaya_internal_utils_jwtkeys.JWK": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "игрок успешно зарегистрирован"
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "Ed25519 (OKP)",
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "shumnaya_internal_utils_jwtkeys.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/shumnaya_internal_utils_jwtkeys.JWK"
+                    }
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Access-токен в формате \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
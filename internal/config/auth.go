@@ -0,0 +1,71 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadAdminPlayerIDs читает ADMIN_PLAYER_IDS (ID через запятую) — игроков,
+// которым при запуске выдаётся роль администратора.
+func LoadAdminPlayerIDs(logger *slog.Logger) []uint {
+	var ids []uint
+	for _, raw := range strings.Split(os.Getenv("ADMIN_PLAYER_IDS"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || id == 0 {
+			logger.Warn("некорректный ID в ADMIN_PLAYER_IDS, пропущен", "value", raw)
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
+
+type TokenConfig struct {
+	// AccessTTL — время жизни access-токена (JWT). Отозвать его нельзя, поэтому оно короткое.
+	AccessTTL time.Duration
+	// RefreshTTL — время жизни refresh-токена; после него нужно войти заново.
+	RefreshTTL time.Duration
+}
+
+func LoadTokenConfig(logger *slog.Logger) TokenConfig {
+	return TokenConfig{
+		AccessTTL:  durationFromEnv(logger, "ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTTL: durationFromEnv(logger, "REFRESH_TOKEN_TTL", 30*24*time.Hour),
+	}
+}
+
+type AccountTokenConfig struct {
+	// Secret подписывает ссылки подтверждения email и сброса пароля
+	Secret []byte
+	// VerifyTTL — срок действия ссылки подтверждения email
+	VerifyTTL time.Duration
+	// ResetTTL — срок действия ссылки сброса пароля; короткий, ссылка даёт доступ к учётной записи
+	ResetTTL time.Duration
+}
+
+// LoadAccountTokenConfig читает ACCOUNT_TOKEN_SECRET; если он не задан, ссылки
+// подписываются JWT_SECRET — прежним секретом access-токенов, который остался
+// в окружении развёрнутых экземпляров.
+func LoadAccountTokenConfig(logger *slog.Logger) AccountTokenConfig {
+	secret := os.Getenv("ACCOUNT_TOKEN_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" {
+		logger.Warn("не заданы ACCOUNT_TOKEN_SECRET и JWT_SECRET, ссылки из писем не будут приниматься")
+	}
+
+	return AccountTokenConfig{
+		Secret:    []byte(secret),
+		VerifyTTL: durationFromEnv(logger, "EMAIL_VERIFY_TTL", 48*time.Hour),
+		ResetTTL:  durationFromEnv(logger, "PASSWORD_RESET_TTL", time.Hour),
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// durationFromEnv читает длительность в формате time.ParseDuration ("48h", "15m").
+// При пустом или некорректном значении возвращает def.
+func durationFromEnv(logger *slog.Logger, key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		logger.Warn("некорректное значение длительности, используется значение по умолчанию",
+			"key", key, "value", raw, "default", def.String())
+		return def
+	}
+
+	return d
+}
+
+// intFromEnv читает целое неотрицательное число. При пустом или некорректном значении возвращает def.
+func intFromEnv(logger *slog.Logger, key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		logger.Warn("некорректное целое значение, используется значение по умолчанию",
+			"key", key, "value", raw, "default", def)
+		return def
+	}
+
+	return n
+}
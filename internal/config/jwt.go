@@ -0,0 +1,75 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/utils/jwtkeys"
+)
+
+type JWTConfig struct {
+	// Algorithm — RS256 или EdDSA; смена алгоритма вступает в силу со следующей ротации
+	Algorithm string
+	// Issuer и Audience записываются в iss и aud и проверяются при разборе токена
+	Issuer   string
+	Audience string
+
+	// RotationInterval — как часто выпускается новый ключ подписи
+	RotationInterval time.Duration
+	// GracePeriod — сколько выведенный ключ ещё проверяет токены; не меньше времени жизни access-токена
+	GracePeriod time.Duration
+	// CheckInterval — как часто проверять, пора ли ротация, и перечитывать ключи других экземпляров
+	CheckInterval time.Duration
+	// PublishDelay — сколько новый ключ публикуется в JWKS, прежде чем начнёт подписывать токены.
+	// Не меньше CheckInterval и срока кеширования JWKS вместе: за это время его загрузят все
+	// экземпляры сервиса и перезапросят потребители JWKS.
+	PublishDelay time.Duration
+}
+
+func LoadJWTConfig(logger *slog.Logger, tokens TokenConfig) JWTConfig {
+	cfg := JWTConfig{
+		Algorithm:        strings.TrimSpace(os.Getenv("JWT_ALGORITHM")),
+		Issuer:           os.Getenv("JWT_ISSUER"),
+		Audience:         os.Getenv("JWT_AUDIENCE"),
+		RotationInterval: durationFromEnv(logger, "JWT_KEY_ROTATION_INTERVAL", 30*24*time.Hour),
+		GracePeriod:      durationFromEnv(logger, "JWT_KEY_GRACE_PERIOD", 24*time.Hour),
+		CheckInterval:    durationFromEnv(logger, "JWT_KEY_CHECK_INTERVAL", time.Minute),
+	}
+	minPublishDelay := cfg.CheckInterval + jwtkeys.JWKSCacheMaxAge
+	cfg.PublishDelay = durationFromEnv(logger, "JWT_KEY_PUBLISH_DELAY", minPublishDelay)
+
+	switch cfg.Algorithm {
+	case models.SigningAlgorithmRS256, models.SigningAlgorithmEdDSA:
+	case "":
+		cfg.Algorithm = models.SigningAlgorithmEdDSA
+	default:
+		logger.Warn("неизвестный алгоритм подписи, используется EdDSA", "key", "JWT_ALGORITHM", "value", cfg.Algorithm)
+		cfg.Algorithm = models.SigningAlgorithmEdDSA
+	}
+
+	if cfg.Issuer == "" {
+		cfg.Issuer = "shumnaya"
+	}
+	if cfg.Audience == "" {
+		cfg.Audience = "shumnaya-api"
+	}
+
+	// Иначе токены, подписанные выведенным ключом, перестанут приниматься раньше своего срока
+	if cfg.GracePeriod < tokens.AccessTTL {
+		logger.Warn("JWT_KEY_GRACE_PERIOD меньше времени жизни access-токена, увеличен",
+			"grace_period", cfg.GracePeriod.String(), "access_ttl", tokens.AccessTTL.String())
+		cfg.GracePeriod = tokens.AccessTTL
+	}
+
+	// Иначе экземпляры, не успевшие перечитать ключи, отклонят токены нового ключа
+	if cfg.PublishDelay < minPublishDelay {
+		logger.Warn("JWT_KEY_PUBLISH_DELAY меньше JWT_KEY_CHECK_INTERVAL и срока кеширования JWKS, увеличен",
+			"publish_delay", cfg.PublishDelay.String(), "min", minPublishDelay.String())
+		cfg.PublishDelay = minPublishDelay
+	}
+
+	return cfg
+}
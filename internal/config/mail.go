@@ -0,0 +1,63 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	MailDriverSMTP = "smtp"
+	MailDriverFile = "file"
+	MailDriverLog  = "log"
+)
+
+type MailConfig struct {
+	// Driver — smtp, file (письма в Dir) или log (только в лог)
+	Driver string
+	From   string
+	Dir    string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// BaseURL — адрес клиента, на который ведут ссылки из писем
+	BaseURL string
+}
+
+func LoadMailConfig(logger *slog.Logger) MailConfig {
+	cfg := MailConfig{
+		Driver:       strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_DRIVER"))),
+		From:         os.Getenv("MAIL_FROM"),
+		Dir:          os.Getenv("MAIL_DIR"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     587,
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		BaseURL:      strings.TrimRight(os.Getenv("APP_BASE_URL"), "/"),
+	}
+
+	if cfg.Driver == "" {
+		cfg.Driver = MailDriverLog
+	}
+	if cfg.From == "" {
+		cfg.From = "no-reply@localhost"
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "http://localhost:8080"
+	}
+
+	if raw := os.Getenv("SMTP_PORT"); raw != "" {
+		port, err := strconv.Atoi(raw)
+		if err != nil || port <= 0 || port > 65535 {
+			logger.Warn("некорректный SMTP_PORT, используется значение по умолчанию", "value", raw, "default", cfg.SMTPPort)
+		} else {
+			cfg.SMTPPort = port
+		}
+	}
+
+	return cfg
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+)
+
+type MatchConfirmationConfig struct {
+	// Timeout — через сколько неподтверждённый матч подтверждается автоматически.
+	Timeout time.Duration
+	// Interval — как часто воркер проверяет просроченные матчи.
+	Interval time.Duration
+}
+
+func LoadMatchConfirmationConfig(logger *slog.Logger) MatchConfirmationConfig {
+	return MatchConfirmationConfig{
+		Timeout:  durationFromEnv(logger, "MATCH_CONFIRM_TIMEOUT", 72*time.Hour),
+		Interval: durationFromEnv(logger, "MATCH_CONFIRM_INTERVAL", 10*time.Minute),
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"shumnaya/internal/ratelimit"
+)
+
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+)
+
+type RateLimitConfig struct {
+	// Store — memory (один экземпляр сервиса) или postgres (общие лимиты для нескольких экземпляров)
+	Store         string
+	SweepInterval time.Duration
+
+	LoginPerIP      ratelimit.Limit
+	LoginPerEmail   ratelimit.Limit
+	AccountPerIP    ratelimit.Limit // регистрация и письма сброса пароля
+	AccountPerEmail ratelimit.Limit
+	WritePerPlayer  ratelimit.Limit // изменяющие запросы авторизованного игрока
+
+	// Блокировка входа после неудачных попыток: по email и (с большим порогом) по IP
+	LoginLockout   ratelimit.LockoutPolicy
+	LoginIPLockout ratelimit.LockoutPolicy
+
+	// TrustedProxies — адреса и подсети прокси, чьему X-Forwarded-For верится при определении
+	// IP клиента. Пусто — заголовку не верится: иначе клиент обходит лимиты по IP, подставляя его.
+	TrustedProxies []string
+}
+
+func LoadRateLimitConfig(logger *slog.Logger) RateLimitConfig {
+	store := strings.ToLower(strings.TrimSpace(os.Getenv("RATE_LIMIT_STORE")))
+	switch store {
+	case RateLimitStoreMemory, RateLimitStorePostgres:
+	case "":
+		store = RateLimitStoreMemory
+	default:
+		logger.Warn("неизвестное хранилище лимитов, используется memory", "key", "RATE_LIMIT_STORE", "value", store)
+		store = RateLimitStoreMemory
+	}
+
+	lockout := ratelimit.LockoutPolicy{
+		Threshold: intFromEnv(logger, "LOGIN_LOCKOUT_THRESHOLD", 5),
+		Window:    durationFromEnv(logger, "LOGIN_LOCKOUT_WINDOW", 15*time.Minute),
+		Base:      durationFromEnv(logger, "LOGIN_LOCKOUT_BASE", time.Minute),
+		Max:       durationFromEnv(logger, "LOGIN_LOCKOUT_MAX", time.Hour),
+	}
+	ipLockout := lockout
+	ipLockout.Threshold = intFromEnv(logger, "LOGIN_LOCKOUT_IP_THRESHOLD", 20)
+
+	return RateLimitConfig{
+		Store:           store,
+		SweepInterval:   durationFromEnv(logger, "RATE_LIMIT_SWEEP_INTERVAL", 10*time.Minute),
+		LoginPerIP:      limitFromEnv(logger, "LOGIN_LIMIT_IP", ratelimit.Limit{Burst: 20, Per: time.Minute}),
+		LoginPerEmail:   limitFromEnv(logger, "LOGIN_LIMIT_EMAIL", ratelimit.Limit{Burst: 5, Per: time.Minute}),
+		AccountPerIP:    limitFromEnv(logger, "ACCOUNT_LIMIT_IP", ratelimit.Limit{Burst: 10, Per: time.Hour}),
+		AccountPerEmail: limitFromEnv(logger, "ACCOUNT_LIMIT_EMAIL", ratelimit.Limit{Burst: 3, Per: time.Hour}),
+		WritePerPlayer:  limitFromEnv(logger, "WRITE_LIMIT_PLAYER", ratelimit.Limit{Burst: 30, Per: time.Minute}),
+		LoginLockout:    lockout,
+		LoginIPLockout:  ipLockout,
+		TrustedProxies:  listFromEnv("TRUSTED_PROXIES"),
+	}
+}
+
+// listFromEnv читает значения через запятую, пустые пропускает.
+func listFromEnv(key string) []string {
+	var values []string
+	for _, raw := range strings.Split(os.Getenv(key), ",") {
+		if raw = strings.TrimSpace(raw); raw != "" {
+			values = append(values, raw)
+		}
+	}
+	return values
+}
+
+// limitFromEnv читает лимит в формате "<запросов>/<длительность>", например "5/1m".
+// "0" отключает лимит.
+func limitFromEnv(logger *slog.Logger, key string, def ratelimit.Limit) ratelimit.Limit {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return def
+	}
+	if raw == "0" {
+		return ratelimit.Limit{}
+	}
+
+	burstRaw, perRaw, ok := strings.Cut(raw, "/")
+	burst, burstErr := strconv.Atoi(burstRaw)
+	per, perErr := time.ParseDuration(perRaw)
+	if !ok || burstErr != nil || perErr != nil || burst <= 0 || per <= 0 {
+		logger.Warn("некорректный лимит, используется значение по умолчанию",
+			"key", key, "value", raw, "default", def.String())
+		return def
+	}
+
+	return ratelimit.Limit{Burst: burst, Per: per}
+}
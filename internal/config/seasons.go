@@ -0,0 +1,31 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	"shumnaya/internal/models"
+)
+
+type SeasonSchedulerConfig struct {
+	// Interval — как часто планировщик закрывает и открывает сезоны.
+	Interval time.Duration
+	// Recurrence — шаблон автоматически создаваемых сезонов (monthly, quarterly); пусто — не создавать.
+	Recurrence string
+}
+
+func LoadSeasonSchedulerConfig(logger *slog.Logger) SeasonSchedulerConfig {
+	recurrence := os.Getenv("SEASON_RECURRENCE")
+	switch recurrence {
+	case "", models.SeasonRecurrenceMonthly, models.SeasonRecurrenceQuarterly:
+	default:
+		logger.Warn("некорректный шаблон сезонов, автосоздание отключено", "key", "SEASON_RECURRENCE", "value", recurrence)
+		recurrence = ""
+	}
+
+	return SeasonSchedulerConfig{
+		Interval:   durationFromEnv(logger, "SEASON_SCHEDULER_INTERVAL", 5*time.Minute),
+		Recurrence: recurrence,
+	}
+}
@@ -1,10 +1,41 @@
 package dto
 
+import "shumnaya/internal/models"
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginResponse — пара токенов. Token — короткоживущий access-токен для заголовка
+// Authorization, RefreshToken обменивается на новую пару через POST /auth/refresh.
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in" example:"900"` // секунд до истечения access-токена
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func NewLoginResponse(tokens *models.AuthTokens) LoginResponse {
+	return LoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	}
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email" example:"test@mail.com"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6" example:"secret123"`
 }
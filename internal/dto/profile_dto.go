@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"shumnaya/internal/models"
+)
+
+type PublicPlayer struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Rating int    `json:"rating"`
+}
+
+// PublicPlayerProfile — профиль игрока, который видит любой авторизованный игрок.
+type PublicPlayerProfile struct {
+	Player        PublicPlayer        `json:"player"`
+	Rating        int                 `json:"rating"`
+	TotalMatches  int                 `json:"total_matches"`
+	Wins          int                 `json:"wins"`
+	Losses        int                 `json:"losses"`
+	Singles       models.MatchSummary `json:"singles"`
+	Doubles       models.MatchSummary `json:"doubles"`
+	RecentMatches []models.Match      `json:"recent_matches,omitempty"`
+}
+
+// PrivatePlayerProfile — профиль, который игрок видит сам о себе: публичный профиль
+// и данные учётной записи.
+type PrivatePlayerProfile struct {
+	PublicPlayerProfile
+	Account PlayerAccount `json:"account"`
+}
+
+type PlayerAccount struct {
+	Email         string    `json:"email" example:"test@mail.com"`
+	EmailVerified bool      `json:"email_verified"`
+	Role          string    `json:"role" example:"player"`
+	RegisteredAt  time.Time `json:"registered_at"`
+
+	// Неопределённость рейтинга (Glicko-2, TrueSkill) — служебные сведения, в публичный профиль не входят
+	RatingDeviation  float64 `json:"rating_deviation"`
+	RatingVolatility float64 `json:"rating_volatility"`
+}
+
+func NewPublicPlayerProfile(p *models.PlayerProfile) PublicPlayerProfile {
+	return PublicPlayerProfile{
+		Player: PublicPlayer{
+			ID:     p.Player.ID,
+			Name:   p.Player.Name,
+			Rating: p.Player.Rating,
+		},
+		Rating:        p.Rating,
+		TotalMatches:  p.TotalMatches,
+		Wins:          p.Wins,
+		Losses:        p.Losses,
+		Singles:       p.Singles,
+		Doubles:       p.Doubles,
+		RecentMatches: p.RecentMatches,
+	}
+}
+
+func NewPrivatePlayerProfile(p *models.PlayerProfile) PrivatePlayerProfile {
+	return PrivatePlayerProfile{
+		PublicPlayerProfile: NewPublicPlayerProfile(p),
+		Account: PlayerAccount{
+			Email:            p.Player.Email,
+			EmailVerified:    p.Player.EmailVerified,
+			Role:             p.Player.Role,
+			RegisteredAt:     p.Player.CreatedAt,
+			RatingDeviation:  p.Player.RatingDeviation,
+			RatingVolatility: p.Player.RatingVolatility,
+		},
+	}
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=player organizer admin" example:"organizer"`
+}
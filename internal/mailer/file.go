@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer — замена SMTP для локальной разработки: письмо пишется в лог целиком
+// (вместе со ссылкой), а если задан dir — ещё и сохраняется в нём файлом .eml.
+type FileMailer struct {
+	dir    string
+	from   string
+	logger *slog.Logger
+}
+
+func NewFileMailer(dir, from string, logger *slog.Logger) *FileMailer {
+	return &FileMailer{dir: dir, from: from, logger: logger}
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	m.logger.Info("письмо не отправлено (локальный режим)", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+
+	if m.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("mailer: create %s: %w", m.dir, err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000000"), safeFileName(msg.To))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, render(m.from, msg), 0o644); err != nil {
+		return fmt.Errorf("mailer: write %s: %w", path, err)
+	}
+	return nil
+}
+
+func safeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
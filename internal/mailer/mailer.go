@@ -0,0 +1,38 @@
+// Package mailer отправляет служебные письма: подтверждение email и сброс пароля.
+package mailer
+
+import (
+	"fmt"
+	"log/slog"
+
+	"shumnaya/internal/config"
+)
+
+// Message — простое текстовое письмо одному получателю.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New выбирает реализацию по MAIL_DRIVER: smtp — настоящая отправка,
+// file — письма складываются в MAIL_DIR, log — только пишутся в лог (для локальной разработки).
+func New(cfg config.MailConfig, logger *slog.Logger) (Mailer, error) {
+	switch cfg.Driver {
+	case config.MailDriverSMTP:
+		if cfg.SMTPHost == "" || cfg.From == "" {
+			return nil, fmt.Errorf("mailer: smtp driver requires SMTP_HOST and MAIL_FROM")
+		}
+		return NewSMTPMailer(cfg), nil
+	case config.MailDriverFile:
+		return NewFileMailer(cfg.Dir, cfg.From, logger), nil
+	case config.MailDriverLog:
+		return NewFileMailer("", cfg.From, logger), nil
+	default:
+		return nil, fmt.Errorf("mailer: unknown MAIL_DRIVER %q", cfg.Driver)
+	}
+}
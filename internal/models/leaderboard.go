@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// LeaderboardFilter — отбор игроков в рейтинг-лист. С SeasonID игроки ранжируются
+// по сезонному рейтингу разряда Type (по умолчанию одиночного) и в лист попадают только
+// сыгравшие в сезоне; без сезона — по общему рейтингу, а Type ограничивает подсчёт матчей.
+type LeaderboardFilter struct {
+	SeasonID    *uint
+	Type        *string
+	MinGames    int        // не меньше стольких подтверждённых матчей
+	ActiveSince *time.Time // последний матч не раньше этого момента
+}
+
+type LeaderboardEntry struct {
+	Rank        int        `json:"rank"` // игроки с равным рейтингом делят место
+	PlayerID    uint       `json:"player_id"`
+	Name        string     `json:"name"`
+	Rating      int        `json:"rating"`
+	Games       int        `json:"games"`
+	Wins        int        `json:"wins"`
+	Losses      int        `json:"losses"`
+	LastMatchAt *time.Time `json:"last_match_at,omitempty"`
+
+	// Provisional — у игрока меньше elo.ProvisionalGames матчей, рейтинг ещё не устоялся
+	Provisional bool `json:"provisional"`
+	// IsMe — строка авторизованного игрока, запросившего лист
+	IsMe bool `json:"is_me,omitempty"`
+}
+
+// LeaderboardPage — страница рейтинг-листа. Me — место авторизованного игрока
+// при тех же фильтрах, даже если оно не попало на страницу.
+type LeaderboardPage struct {
+	Data []LeaderboardEntry `json:"data"`
+	PageInfo
+	Me *LeaderboardEntry `json:"me,omitempty"`
+}
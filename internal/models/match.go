@@ -6,8 +6,29 @@ import (
 	"gorm.io/gorm"
 )
 
+// Статусы матча: рейтинги и таблицы учитывают только подтверждённые матчи.
+const (
+	MatchStatusPending   = "pending"
+	MatchStatusConfirmed = "confirmed"
+	MatchStatusDisputed  = "disputed"
+)
+
+// ForfeitScore — счёт матча, засчитанного за неявку.
+const ForfeitScore = "w/o"
+
+// Разряды матчей: одиночные и парные. Таблицы сезона ведутся по каждому разряду отдельно.
+const (
+	MatchTypeSingles = "singles"
+	MatchTypeDoubles = "doubles"
+)
+
 type Match struct {
-	gorm.Model `json:"-"`
+	// Поля gorm.Model: id открыт клиентам, остальное скрыто.
+
+	ID        uint           `json:"id" gorm:"primarykey"` // по id матч подтверждают, оспаривают, исправляют и удаляют
+	CreatedAt time.Time      `json:"-"`
+	UpdatedAt time.Time      `json:"-"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
 	WinnerID uint   `json:"winner_id" gorm:"column:winner_id;index:idx_matches_winner_date;index:idx_matches_head_to_head" binding:"required,min=1"`
 	Winner   Player `json:"-" gorm:"foreignKey:WinnerID;references:ID"`
@@ -15,34 +36,162 @@ type Match struct {
 	LoserID uint   `json:"loser_id" gorm:"column:loser_id;index:idx_matches_loser_date;index:idx_matches_head_to_head" binding:"required,min=1"`
 	Loser   Player `json:"-" gorm:"foreignKey:LoserID;references:ID"`
 
+	// Партнёры в парном матче. WinnerID/LoserID — первые игроки каждой пары.
+	WinnerPartnerID *uint `json:"winner_partner_id,omitempty" gorm:"column:winner_partner_id;index"`
+	LoserPartnerID  *uint `json:"loser_partner_id,omitempty" gorm:"column:loser_partner_id;index"`
+
 	SeasonID uint   `json:"season_id" gorm:"column:season_id;index" binding:"required,min=1"`
 	Season   Season `json:"-" gorm:"foreignKey:SeasonID;references:ID"`
 
-	Score              string    `json:"score" gorm:"column:score" binding:"required"`
-	WinnerRatingChange int       `json:"winner_rating_change,omitempty" gorm:"column:winner_rating_change"`
-	LoserRatingChange  int       `json:"loser_rating_change,omitempty" gorm:"column:loser_rating_change"`
-	PlayedAt           time.Time `json:"played_at" gorm:"column:played_at;index:idx_matches_winner_date;index:idx_matches_loser_date"`
+	Type string `json:"type" gorm:"column:type;type:varchar(16);default:singles;index"`
+
+	// Неявка: матч засчитывается в таблицу по очкам за неявку, счёта нет, рейтинг не меняется
+	Forfeit bool `json:"forfeit,omitempty" gorm:"column:forfeit;default:false"`
+
+	Score              string `json:"score" gorm:"column:score" binding:"required"`
+	WinnerGames        int    `json:"winner_games" gorm:"column:winner_games;index:idx_matches_score,priority:1"`
+	LoserGames         int    `json:"loser_games" gorm:"column:loser_games;index:idx_matches_score,priority:2"`
+	WinnerRatingChange int    `json:"winner_rating_change,omitempty" gorm:"column:winner_rating_change"`
+	LoserRatingChange  int    `json:"loser_rating_change,omitempty" gorm:"column:loser_rating_change"`
+
+	WinnerPartnerRatingChange int `json:"winner_partner_rating_change,omitempty" gorm:"column:winner_partner_rating_change"`
+	LoserPartnerRatingChange  int `json:"loser_partner_rating_change,omitempty" gorm:"column:loser_partner_rating_change"`
+
+	// Очки в таблицу, начисленные каждому игроку стороны по схеме сезона. Матчи, проведённые
+	// до появления схемы, давали победителю 1 очко (см. repository.PrepareMigrations).
+	WinnerStandingPoints int `json:"winner_standing_points" gorm:"column:winner_standing_points"`
+	LoserStandingPoints  int `json:"loser_standing_points" gorm:"column:loser_standing_points;default:0"`
+
+	// Рейтинги сторон перед матчем (для пары — по правилу сезона DoublesTeamRating).
+	// Пусты у матчей, проведённых до появления полей, пока история не пересчитана.
+	WinnerRatingBefore *int `json:"winner_rating_before,omitempty" gorm:"column:winner_rating_before"`
+	LoserRatingBefore  *int `json:"loser_rating_before,omitempty" gorm:"column:loser_rating_before"`
+
+	// Какая система посчитала рейтинг и какую вероятность победы она давала победителю до матча
+	RatingEngine         string  `json:"rating_engine,omitempty" gorm:"column:rating_engine;type:varchar(16)"`
+	WinnerWinProbability float64 `json:"winner_win_probability,omitempty" gorm:"column:winner_win_probability"`
+
+	PlayedAt time.Time `json:"played_at" gorm:"column:played_at;index:idx_matches_winner_date;index:idx_matches_loser_date;index:idx_matches_status_date,priority:2"`
+	Venue    string    `json:"venue,omitempty" gorm:"column:venue;type:varchar(255)"` // где играли
+
+	// Подтверждение результата соперником. Матчи до появления подтверждений считаются подтверждёнными.
+	Status        string     `json:"status" gorm:"column:status;type:varchar(16);default:confirmed;index:idx_matches_status_date,priority:1"`
+	ReportedByID  *uint      `json:"reported_by_id,omitempty" gorm:"column:reported_by_id"`
+	ConfirmedAt   *time.Time `json:"confirmed_at,omitempty" gorm:"column:confirmed_at"`
+	DisputeReason string     `json:"dispute_reason,omitempty" gorm:"column:dispute_reason"`
+
+	Games []MatchGame `json:"games,omitempty" gorm:"foreignKey:MatchID"` // счёт по партиям
 }
 
+// Исход матча для игрока из фильтра.
+const (
+	MatchResultWon  = "won"
+	MatchResultLost = "lost"
+)
+
+// MatchFilter — фильтры списка матчей, все условия объединяются через AND.
+// OpponentID и Result считаются относительно PlayerID и без него не применяются.
 type MatchFilter struct {
 	SeasonID *uint      `json:"season_id"`
+	Status   *string    `json:"status"`
+	Type     *string    `json:"type"`
 	PlayerID *uint      `json:"player_id"`
 	FromDate *time.Time `json:"from_date"`
 	ToDate   *time.Time `json:"to_date"`
+
+	OpponentID *uint   `json:"opponent_id"`
+	Result     *string `json:"result"`
+	Venue      *string `json:"venue"`
+
+	// Счёт по партиям, например 3:0
+	WinnerGames *int `json:"winner_games"`
+	LoserGames  *int `json:"loser_games"`
+
+	// Разница рейтингов победителя и проигравшего перед матчем; отрицательная — победа
+	// более слабой стороны. UpsetsOnly оставляет только такие матчи.
+	MinRatingDiff *int `json:"min_rating_diff"`
+	MaxRatingDiff *int `json:"max_rating_diff"`
+	UpsetsOnly    bool `json:"upsets_only"`
 }
 
 type CreateMatchRequest struct {
-	WinnerID uint   `json:"winner_id" binding:"required"`
-	LoserID  uint   `json:"loser_id" binding:"required"`
-	SeasonID uint   `json:"season_id" binding:"required"`
-	Score    string `json:"score" binding:"required"`
+	WinnerID uint `json:"winner_id" binding:"required"`
+	LoserID  uint `json:"loser_id" binding:"required"`
+	SeasonID uint `json:"season_id" binding:"required"`
+
+	// Для парного матча передаются оба партнёра
+	WinnerPartnerID *uint `json:"winner_partner_id" binding:"omitempty,min=1"`
+	LoserPartnerID  *uint `json:"loser_partner_id" binding:"omitempty,min=1"`
+
+	Score   string `json:"score" binding:"required_unless=Forfeit true" example:"11-9 7-11 11-5 11-8"` // очки по партиям, первым идёт победитель
+	Forfeit bool   `json:"forfeit"`                                                                    // проигравший не явился, счёт не нужен
+	Venue   string `json:"venue" binding:"omitempty,max=255" example:"Зал №2"`
+}
+
+type UpdateMatchRequest struct {
+	WinnerID uint `json:"winner_id" binding:"required"`
+	LoserID  uint `json:"loser_id" binding:"required"`
+	SeasonID uint `json:"season_id" binding:"required"`
+
+	// Для парного матча передаются оба партнёра
+	WinnerPartnerID *uint `json:"winner_partner_id" binding:"omitempty,min=1"`
+	LoserPartnerID  *uint `json:"loser_partner_id" binding:"omitempty,min=1"`
+
+	Score   string `json:"score" binding:"required_unless=Forfeit true" example:"11-9 7-11 11-5 11-8"`
+	Forfeit bool   `json:"forfeit"`
+	Venue   string `json:"venue" binding:"omitempty,max=255" example:"Зал №2"`
 }
 
+type DisputeMatchRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"счёт последней партии был 9-11"`
+}
+
+// HeadToHeadRecord — история встреч двух игроков на разных сторонах подтверждённых матчей.
 type HeadToHeadRecord struct {
-	PlayerAID         uint    `json:"player_a_id"`
-	PlayerBID         uint    `json:"player_b_id"`
-	PlayerAWins       int     `json:"player_a_wins"`
-	PlayerBWins       int     `json:"player_b_wins"`
-	TotalMatches      int     `json:"total_matches"`
+	PlayerAID    uint   `json:"player_a_id"`
+	PlayerBID    uint   `json:"player_b_id"`
+	Type         string `json:"type"`
+	PlayerAWins  int    `json:"player_a_wins"`
+	PlayerBWins  int    `json:"player_b_wins"`
+	TotalMatches int    `json:"total_matches"`
+
+	// Выигранные партии и очки по счёту матчей
+	PlayerAGames  int `json:"player_a_games"`
+	PlayerBGames  int `json:"player_b_games"`
+	PlayerAPoints int `json:"player_a_points"`
+	PlayerBPoints int `json:"player_b_points"`
+
+	// Сумма изменений рейтинга каждого игрока в этих матчах
+	PlayerARatingChange int `json:"player_a_rating_change"`
+	PlayerBRatingChange int `json:"player_b_rating_change"`
+
+	Streaks    HeadToHeadStreaks    `json:"streaks"`
+	Seasons    []HeadToHeadSeason   `json:"seasons"`
+	Prediction HeadToHeadPrediction `json:"prediction"`
+
 	LastMatchesPlayed []Match `json:"last_matches_played"`
 }
+
+// HeadToHeadStreaks — серии побед внутри противостояния. Current положительная,
+// если последние встречи подряд выигрывает игрок A, и отрицательная, если игрок B.
+type HeadToHeadStreaks struct {
+	Current  int `json:"current"`
+	LongestA int `json:"longest_a"`
+	LongestB int `json:"longest_b"`
+}
+
+type HeadToHeadSeason struct {
+	SeasonID     uint   `json:"season_id"`
+	SeasonName   string `json:"season_name"`
+	TotalMatches int    `json:"total_matches"`
+	PlayerAWins  int    `json:"player_a_wins"`
+	PlayerBWins  int    `json:"player_b_wins"`
+}
+
+// HeadToHeadPrediction — прогноз следующей встречи по формуле Эло и текущим рейтингам.
+type HeadToHeadPrediction struct {
+	PlayerARating         int     `json:"player_a_rating"`
+	PlayerBRating         int     `json:"player_b_rating"`
+	PlayerAWinProbability float64 `json:"player_a_win_probability"`
+	PlayerBWinProbability float64 `json:"player_b_win_probability"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// MatchGame — счёт одной партии матча. Очки записаны с точки зрения победителя матча.
+type MatchGame struct {
+	gorm.Model `json:"-"`
+
+	MatchID uint `json:"-" gorm:"column:match_id;index:idx_match_games_match_number"`
+
+	Number       int `json:"number" gorm:"column:number;index:idx_match_games_match_number"`
+	WinnerPoints int `json:"winner_points" gorm:"column:winner_points"`
+	LoserPoints  int `json:"loser_points" gorm:"column:loser_points"`
+}
@@ -0,0 +1,59 @@
+package models
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// Сортировки списков. Минус в начале — по убыванию.
+const (
+	MatchSortPlayedAtDesc = "-played_at"
+	MatchSortPlayedAtAsc  = "played_at"
+
+	SeasonSortStartDateDesc = "-start_date"
+	SeasonSortStartDateAsc  = "start_date"
+
+	PlayerSortNameAsc        = "name"
+	PlayerSortNameDesc       = "-name"
+	PlayerSortRatingDesc     = "-rating"
+	PlayerSortRatingAsc      = "rating"
+	PlayerSortLastActiveDesc = "-last_active"
+	PlayerSortLastActiveAsc  = "last_active"
+)
+
+// PageRequest — параметры страницы списка. Cursor — next_cursor предыдущей страницы,
+// пустой для первой. Курсор действителен только для той же сортировки.
+type PageRequest struct {
+	Limit     int
+	Cursor    string
+	Sort      string
+	WithTotal bool
+}
+
+// PageInfo — сведения о странице. Total заполняется только по запросу (with_total).
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+type MatchPage struct {
+	Data []Match `json:"data"`
+	PageInfo
+}
+
+type SeasonPage struct {
+	Data []Season `json:"data"`
+	PageInfo
+}
+
+type StandingPage struct {
+	Data []Standing `json:"data"`
+	PageInfo
+}
+
+type PlayerSummaryPage struct {
+	Data []PlayerSummary `json:"data"`
+	PageInfo
+}
@@ -1,23 +1,52 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// Player — модель хранения. Email и хеш пароля не сериализуются никогда: игрок вложен
+// в таблицы и итоги сезонов, а профили отдаются через dto.PublicPlayerProfile и dto.PrivatePlayerProfile.
 type Player struct {
-	gorm.Model `json:"-"`
-	Name         string  `json:"name" gorm:"column:name;type:varchar(255)" binding:"required"`
-	Email        string  `json:"email" gorm:"column:email;type:varchar(255);uniqueIndex" binding:"required,email"`
-	PasswordHash string  `json:"password_hash,omitempty" gorm:"column:password_hash"`
-	Rating       int     `json:"rating" gorm:"column:rating" binding:"min=0"`
+	gorm.Model   `json:"-"`
+	Name         string `json:"name" gorm:"column:name;type:varchar(255)" binding:"required"`
+	Email        string `json:"-" gorm:"column:email;type:varchar(255);uniqueIndex" binding:"required,email"`
+	PasswordHash string `json:"-" gorm:"column:password_hash"`
+	Rating       int    `json:"rating" gorm:"column:rating" binding:"min=0"`
+	Role         string `json:"role" gorm:"column:role;type:varchar(16);default:player"`
+
+	// EmailVerified — игрок перешёл по ссылке из письма. Без подтверждения нельзя записывать матчи.
+	EmailVerified bool `json:"-" gorm:"column:email_verified;not null;default:false"`
+
+	// Неопределённость рейтинга для Glicko-2 (RD и волатильность) и TrueSkill (σ)
+	RatingDeviation  float64 `json:"rating_deviation" gorm:"column:rating_deviation;default:350"`
+	RatingVolatility float64 `json:"rating_volatility" gorm:"column:rating_volatility;default:0.06"`
 
 	Matches []Match `json:"matches,omitempty" gorm:"-"` // история матчей по игроку (поле для удобства, запросы через репозиторий)
 }
 
-
 type PlayerProfile struct {
-	Player        Player  `json:"player"`
-	Rating        int     `json:"rating"`
-	TotalMatches  int     `json:"total_matches"`
-	Wins          int     `json:"wins"`
-	Losses        int     `json:"losses"`
-	RecentMatches []Match `json:"recent_matches,omitempty"`
+	Player        Player       `json:"player"`
+	Rating        int          `json:"rating"`
+	TotalMatches  int          `json:"total_matches"`
+	Wins          int          `json:"wins"`
+	Losses        int          `json:"losses"`
+	Singles       MatchSummary `json:"singles"`
+	Doubles       MatchSummary `json:"doubles"`
+	RecentMatches []Match      `json:"recent_matches,omitempty"`
+}
+
+type MatchSummary struct {
+	TotalMatches int `json:"total_matches"`
+	Wins         int `json:"wins"`
+	Losses       int `json:"losses"`
+}
+
+// PlayerSummary — публичные сведения об игроке для списков: без email и хеша пароля.
+type PlayerSummary struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Rating      int        `json:"rating"`
+	LastMatchAt *time.Time `json:"last_match_at,omitempty"` // последний подтверждённый матч
 }
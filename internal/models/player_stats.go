@@ -0,0 +1,73 @@
+package models
+
+// PlayerStats — статистика игрока по подтверждённым матчам.
+type PlayerStats struct {
+	PlayerID uint   `json:"player_id"`
+	Type     string `json:"type,omitempty"` // пусто — матчи любого разряда
+
+	Summary PlayerStatsSummary `json:"summary"`
+	Streaks PlayerStreaks      `json:"streaks"`
+	Form    PlayerForm         `json:"form"`
+
+	// Победы над соперниками сильнее и слабее по рейтингу перед матчем (без неявок
+	// и матчей без сохранённого рейтинга сторон)
+	VsHigherRated RecordSplit `json:"vs_higher_rated"`
+	VsLowerRated  RecordSplit `json:"vs_lower_rated"`
+
+	Seasons []SeasonSplit `json:"seasons"`
+
+	// Соперники, которых игрок обыгрывает чаще всего, и те, кому он чаще всего проигрывает
+	Favourites []OpponentRecord `json:"favourites"`
+	Nemeses    []OpponentRecord `json:"nemeses"`
+}
+
+type PlayerStatsSummary struct {
+	Matches int     `json:"matches"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	WinRate float64 `json:"win_rate"`
+
+	// Партии и очки по счёту матчей
+	GamesWon       int     `json:"games_won"`
+	GamesLost      int     `json:"games_lost"`
+	GamesWonRatio  float64 `json:"games_won_ratio"`
+	PointsWon      int     `json:"points_won"`
+	PointsLost     int     `json:"points_lost"`
+	PointsWonRatio float64 `json:"points_won_ratio"`
+}
+
+// PlayerStreaks — серии побед и поражений. Current положительная для серии побед
+// и отрицательная для серии поражений.
+type PlayerStreaks struct {
+	Current     int `json:"current"`
+	LongestWin  int `json:"longest_win"`
+	LongestLoss int `json:"longest_loss"`
+}
+
+// PlayerForm — результаты последних матчей, новые первыми: W — победа, L — поражение.
+type PlayerForm struct {
+	Results string `json:"results" example:"WWLWWLLWWW"`
+	Wins    int    `json:"wins"`
+	Losses  int    `json:"losses"`
+}
+
+type RecordSplit struct {
+	Matches int     `json:"matches"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	WinRate float64 `json:"win_rate"`
+}
+
+type SeasonSplit struct {
+	SeasonID   uint   `json:"season_id"`
+	SeasonName string `json:"season_name"`
+	RecordSplit
+	GamesWon  int `json:"games_won"`
+	GamesLost int `json:"games_lost"`
+}
+
+type OpponentRecord struct {
+	OpponentID uint   `json:"opponent_id"`
+	Name       string `json:"name"`
+	RecordSplit
+}
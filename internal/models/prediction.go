@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// MatchPrediction — прогноз одиночного матча двух игроков по рейтинговой системе сезона.
+type MatchPrediction struct {
+	PlayerAID uint   `json:"player_a_id"`
+	PlayerBID uint   `json:"player_b_id"`
+	SeasonID  *uint  `json:"season_id,omitempty"` // сезон, по правилам которого посчитан прогноз
+	Engine    string `json:"engine"`
+
+	PlayerARating int `json:"player_a_rating"`
+	PlayerBRating int `json:"player_b_rating"`
+
+	PlayerAWinProbability float64 `json:"player_a_win_probability"`
+	PlayerBWinProbability float64 `json:"player_b_win_probability"`
+
+	// Изменение общего рейтинга игроков при каждом исходе
+	IfPlayerAWins RatingSwing `json:"if_player_a_wins"`
+	IfPlayerBWins RatingSwing `json:"if_player_b_wins"`
+}
+
+type RatingSwing struct {
+	PlayerAChange int `json:"player_a_change"`
+	PlayerBChange int `json:"player_b_change"`
+}
+
+// CalibrationFilter ограничивает выборку матчей для проверки прогнозов.
+type CalibrationFilter struct {
+	SeasonID *uint
+	Engine   *string
+	Type     *string
+	FromDate *time.Time
+	ToDate   *time.Time
+}
+
+// CalibrationReport — насколько сохранённые в матчах прогнозы совпали с результатами.
+// Прогноз берётся для игрока с меньшим id, чтобы исходы не сводились к одним победам.
+type CalibrationReport struct {
+	Matches int `json:"matches"`
+
+	// BrierScore — средний квадрат ошибки прогноза (0 — идеально, 0.25 — монетка),
+	// BrierSkill — улучшение относительно прогноза 50/50, LogLoss — средняя логарифмическая ошибка.
+	BrierScore float64 `json:"brier_score"`
+	BrierSkill float64 `json:"brier_skill"`
+	LogLoss    float64 `json:"log_loss"`
+
+	Buckets []CalibrationBucket `json:"buckets"`
+}
+
+// CalibrationBucket — матчи с прогнозом в [From, To): средний прогноз и фактическая доля побед.
+type CalibrationBucket struct {
+	From          float64 `json:"from"`
+	To            float64 `json:"to"`
+	Matches       int     `json:"matches"`
+	MeanPredicted float64 `json:"mean_predicted"`
+	ObservedRate  float64 `json:"observed_rate"`
+}
+
+// CalibrationBucketStats — суммы по корзине прогнозов, из которых собирается отчёт.
+type CalibrationBucketStats struct {
+	Bucket        int
+	Matches       int
+	SumPredicted  float64
+	SumOutcome    float64
+	SumSquaredErr float64
+	SumLogLoss    float64
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RateLimitBucket — корзина токенов ограничителя частоты запросов (хранилище в Postgres).
+type RateLimitBucket struct {
+	Key       string    `gorm:"column:key;type:varchar(255);primaryKey"`
+	Tokens    float64   `gorm:"column:tokens;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null"`
+	ExpiresAt time.Time `gorm:"column:expires_at;index;not null"` // корзина снова полная, запись можно удалить
+}
+
+// RateLimitFailure — счётчик неудачных попыток (например, входа) и блокировка по ним.
+type RateLimitFailure struct {
+	Key           string     `gorm:"column:key;type:varchar(255);primaryKey"`
+	Failures      int        `gorm:"column:failures;not null"`
+	LastFailureAt time.Time  `gorm:"column:last_failure_at;not null"`
+	LockedUntil   *time.Time `gorm:"column:locked_until"`
+	ExpiresAt     time.Time  `gorm:"column:expires_at;index;not null"` // счётчик сброшен и блокировка снята
+}
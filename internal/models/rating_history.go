@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RatingHistory — состояние рейтинга игрока до и после подтверждённого матча.
+// Пишется в той же транзакции, что и изменение рейтинга, и позволяет точно
+// откатить матч при пересчёте истории.
+type RatingHistory struct {
+	gorm.Model `json:"-"`
+
+	PlayerID uint   `json:"player_id" gorm:"column:player_id;index:idx_rating_history_player_date"`
+	MatchID  uint   `json:"match_id" gorm:"column:match_id;index"`
+	SeasonID uint   `json:"season_id" gorm:"column:season_id;index"`
+	Engine   string `json:"engine" gorm:"column:engine;type:varchar(16)"`
+
+	RatingBefore     int     `json:"rating_before" gorm:"column:rating_before"`
+	RatingAfter      int     `json:"rating_after" gorm:"column:rating_after"`
+	DeviationBefore  float64 `json:"deviation_before" gorm:"column:deviation_before"`
+	DeviationAfter   float64 `json:"deviation_after" gorm:"column:deviation_after"`
+	VolatilityBefore float64 `json:"volatility_before" gorm:"column:volatility_before"`
+	VolatilityAfter  float64 `json:"volatility_after" gorm:"column:volatility_after"`
+
+	// Сезонный рейтинг игрока до и после матча (Standing.Rating)
+	SeasonRatingBefore     int     `json:"season_rating_before" gorm:"column:season_rating_before;default:0"`
+	SeasonRatingAfter      int     `json:"season_rating_after" gorm:"column:season_rating_after;default:0"`
+	SeasonDeviationBefore  float64 `json:"season_deviation_before" gorm:"column:season_deviation_before;default:0"`
+	SeasonDeviationAfter   float64 `json:"season_deviation_after" gorm:"column:season_deviation_after;default:0"`
+	SeasonVolatilityBefore float64 `json:"season_volatility_before" gorm:"column:season_volatility_before;default:0"`
+	SeasonVolatilityAfter  float64 `json:"season_volatility_after" gorm:"column:season_volatility_after;default:0"`
+
+	PlayedAt time.Time `json:"played_at" gorm:"column:played_at;index:idx_rating_history_player_date"`
+}
+
+func (RatingHistory) TableName() string {
+	return "rating_history"
+}
+
+type RatingHistoryFilter struct {
+	SeasonID *uint
+	FromDate *time.Time
+	ToDate   *time.Time
+}
+
+// RatingPoint — точка временного ряда рейтинга игрока.
+type RatingPoint struct {
+	PlayedAt     time.Time `json:"played_at"`
+	MatchID      uint      `json:"match_id"`
+	SeasonID     uint      `json:"season_id"`
+	Engine       string    `json:"engine"`
+	RatingBefore int       `json:"rating_before"`
+	Rating       int       `json:"rating"`
+	Change       int       `json:"change"`
+	SeasonRating int       `json:"season_rating"`
+}
+
+type RatingExtreme struct {
+	Rating   int       `json:"rating"`
+	MatchID  uint      `json:"match_id"`
+	PlayedAt time.Time `json:"played_at"`
+}
+
+type RatingTimeline struct {
+	PlayerID      uint           `json:"player_id"`
+	CurrentRating int            `json:"current_rating"`
+	Peak          *RatingExtreme `json:"peak,omitempty"`
+	Lowest        *RatingExtreme `json:"lowest,omitempty"`
+	Points        []RatingPoint  `json:"points"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// RecomputeReport — итог пересчёта рейтингов по всей истории матчей.
+type RecomputeReport struct {
+	DryRun         bool      `json:"dry_run"`
+	StartedAt      time.Time `json:"started_at"`
+	Duration       string    `json:"duration"`
+	Matches        int       `json:"matches"`
+	MatchesChanged int       `json:"matches_changed"`
+	Standings      int       `json:"standings"`
+	PlayersChanged int       `json:"players_changed"`
+
+	// Игроки, чей рейтинг изменился, по убыванию модуля изменения
+	Players []PlayerRatingDiff `json:"players"`
+}
+
+type PlayerRatingDiff struct {
+	PlayerID  uint   `json:"player_id"`
+	Name      string `json:"name"`
+	OldRating int    `json:"old_rating"`
+	NewRating int    `json:"new_rating"`
+	Delta     int    `json:"delta"`
+}
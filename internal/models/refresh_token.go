@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// RefreshToken — долгоживущий токен обновления. В базе хранится только SHA-256 хеш.
+// Токены одной цепочки ротаций (от одного входа) объединены FamilyID: каждый токен
+// действует один раз и при обновлении заменяется следующим токеном семьи.
+type RefreshToken struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+
+	PlayerID  uint   `gorm:"column:player_id;index;not null"`
+	FamilyID  string `gorm:"column:family_id;type:varchar(64);index;not null"`
+	TokenHash string `gorm:"column:token_hash;type:varchar(64);uniqueIndex;not null"`
+
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`    // токен обменян на следующий
+	RevokedAt *time.Time `gorm:"column:revoked_at"` // выход или отзыв семьи
+}
+
+// AuthTokens — пара токенов, которую получает игрок при входе и обновлении.
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration // время жизни access-токена
+}
@@ -0,0 +1,29 @@
+package models
+
+// Роли игроков. Роль хранится в players.role и передаётся в токене.
+const (
+	RolePlayer    = "player"    // записывает и подтверждает свои матчи
+	RoleOrganizer = "organizer" // дополнительно ведёт свои сезоны: создание, активация, закрытие, исправление матчей
+	RoleAdmin     = "admin"     // управляет всеми сезонами, исправлениями, ролями и обслуживанием
+)
+
+var Roles = []string{RolePlayer, RoleOrganizer, RoleAdmin}
+
+// Actor — авторизованный игрок, от имени которого выполняется действие.
+type Actor struct {
+	PlayerID uint
+	Role     string
+}
+
+// CanManageSeason — может ли игрок менять сезон и исправлять его матчи:
+// администратор — любой сезон, организатор — только свой.
+func (a Actor) CanManageSeason(season *Season) bool {
+	switch a.Role {
+	case RoleAdmin:
+		return true
+	case RoleOrganizer:
+		return season.OrganizerID != nil && *season.OrganizerID == a.PlayerID
+	default:
+		return false
+	}
+}
@@ -7,11 +7,80 @@ import (
 )
 
 type Season struct {
-	gorm.Model `json:"-"`
+	// Поля gorm.Model: id открыт клиентам, остальное скрыто.
+
+	ID        uint           `json:"id" gorm:"primarykey"` // по id сезон активируют и закрывают
+	CreatedAt time.Time      `json:"-"`
+	UpdatedAt time.Time      `json:"-"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
 	Name      string    `json:"name" gorm:"column:name;type:varchar(255)" binding:"required"`
 	StartDate time.Time `json:"start_date" gorm:"column:start_date" binding:"required"`
 	EndDate   time.Time `json:"end_date" gorm:"column:end_date" binding:"required"`
-	IsActive  bool      `json:"is_active" gorm:"column:is_active"`
+	// Активным может быть только один сезон; закрытый сезон (ClosedAt) больше не меняется
+	IsActive bool       `json:"is_active" gorm:"column:is_active;uniqueIndex:idx_seasons_single_active,where:is_active AND deleted_at IS NULL"`
+	ClosedAt *time.Time `json:"closed_at,omitempty" gorm:"column:closed_at"`
+
+	// Организатор сезона: кроме администраторов, только он активирует и закрывает сезон
+	// и исправляет его матчи. У сезонов, созданных администратором или планировщиком, может быть пустым.
+	OrganizerID *uint `json:"organizer_id,omitempty" gorm:"column:organizer_id;index"`
+
+	// Формат матчей сезона: best-of-N партий до GamePoints очков (с разницей в 2 очка)
+	BestOf     int `json:"best_of" gorm:"column:best_of;default:5" binding:"omitempty,oneof=1 3 5 7"`
+	GamePoints int `json:"game_points" gorm:"column:game_points;default:11" binding:"omitempty,oneof=11 21"`
+
+	// Как считается рейтинг пары в парных матчах: средний, по сильнейшему или по слабейшему игроку
+	DoublesTeamRating string `json:"doubles_team_rating" gorm:"column:doubles_team_rating;type:varchar(16);default:average" binding:"omitempty,oneof=average strongest weakest"`
+
+	// Рейтинговая система сезона
+	RatingEngine string `json:"rating_engine" gorm:"column:rating_engine;type:varchar(16);default:elo" binding:"omitempty,oneof=elo glicko2 trueskill"`
+
+	// С чего игрок начинает сезонный рейтинг: с общего рейтинга (carry), со сдвига общего
+	// рейтинга к стартовому на RatingRegressPercent процентов (regress) или со стартового (hard)
+	RatingReset          string `json:"rating_reset" gorm:"column:rating_reset;type:varchar(16);default:carry" binding:"omitempty,oneof=carry regress hard"`
+	RatingRegressPercent int    `json:"rating_regress_percent,omitempty" gorm:"column:rating_regress_percent;default:0" binding:"omitempty,min=0,max=100"`
+
+	// Сколько очков в таблицу приносит матч
+	Points PointsScheme `json:"points" gorm:"embedded;embeddedPrefix:points_"`
+
+	// Критерии распределения мест через запятую, например "points,head_to_head,games_ratio"
+	TieBreakers string `json:"tie_breakers" gorm:"column:tie_breakers;type:varchar(255)" example:"points,win_diff,rating"`
 
 	Matches []Match `json:"matches,omitempty" gorm:"foreignKey:SeasonID"` // получение матчей по сезонам
 }
+
+// DefaultWinPoints — очки за победу и за победу неявкой, если схема сезона их не задаёт.
+const DefaultWinPoints = 1
+
+// PointsScheme — начисление очков в таблицу сезона. Очки за участие получают все игроки
+// сыгранного матча, бонусы — победившая сторона. Неявка (forfeit) бонусов и очков за участие не даёт.
+// Win и ForfeitWin — указатели, чтобы явный 0 отличался от незаданного значения (DefaultWinPoints).
+type PointsScheme struct {
+	Win           *int `json:"win" gorm:"column:win" example:"3"`
+	Loss          int  `json:"loss" gorm:"column:loss;default:0" example:"1"`
+	ForfeitWin    *int `json:"forfeit_win" gorm:"column:forfeit_win" example:"3"`
+	ForfeitLoss   int  `json:"forfeit_loss" gorm:"column:forfeit_loss;default:0" example:"-1"`
+	Participation int  `json:"participation" gorm:"column:participation;default:0"`
+	SweepBonus    int  `json:"sweep_bonus" gorm:"column:sweep_bonus;default:0"` // победа всухую
+	UpsetBonus    int  `json:"upset_bonus" gorm:"column:upset_bonus;default:0"` // победа над фаворитом (прогноз на победу ниже 50%)
+}
+
+// WinPoints — очки за победу; у сезонов без заданного значения — DefaultWinPoints.
+func (p PointsScheme) WinPoints() int {
+	if p.Win == nil {
+		return DefaultWinPoints
+	}
+	return *p.Win
+}
+
+// ForfeitWinPoints — очки за победу неявкой; у сезонов без заданного значения — DefaultWinPoints.
+func (p PointsScheme) ForfeitWinPoints() int {
+	if p.ForfeitWin == nil {
+		return DefaultWinPoints
+	}
+	return *p.ForfeitWin
+}
+
+func (s *Season) IsClosed() bool {
+	return s.ClosedAt != nil
+}
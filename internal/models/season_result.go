@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Награды, которые выдаются при закрытии сезона в каждом разряде.
+const (
+	AwardChampion    = "champion"
+	AwardRunnerUp    = "runner_up"
+	AwardThirdPlace  = "third_place"
+	AwardMostWins    = "most_wins"
+	AwardMostMatches = "most_matches"
+	AwardTopRated    = "top_rated"
+)
+
+// SeasonResult — итоговая строка таблицы закрытого сезона. Снимок делается один раз
+// при закрытии и дальше не меняется, даже если живая таблица будет пересчитана.
+type SeasonResult struct {
+	gorm.Model `json:"-"`
+
+	SeasonID uint   `json:"season_id" gorm:"column:season_id;uniqueIndex:idx_season_results_player"`
+	Type     string `json:"type" gorm:"column:type;type:varchar(16);uniqueIndex:idx_season_results_player"`
+	PlayerID uint   `json:"player_id" gorm:"column:player_id;uniqueIndex:idx_season_results_player"`
+	Player   Player `json:"player,omitempty" gorm:"foreignKey:PlayerID;references:ID"`
+
+	Rank   int `json:"rank" gorm:"column:rank"`
+	Wins   int `json:"wins" gorm:"column:wins"`
+	Losses int `json:"losses" gorm:"column:losses"`
+	Points int `json:"points" gorm:"column:points"`
+	Rating int `json:"rating" gorm:"column:rating"` // сезонный рейтинг на момент закрытия
+}
+
+type SeasonAward struct {
+	gorm.Model `json:"-"`
+
+	SeasonID uint   `json:"season_id" gorm:"column:season_id;index"`
+	Type     string `json:"type" gorm:"column:type;type:varchar(16)"`
+	Award    string `json:"award" gorm:"column:award;type:varchar(32)"`
+	PlayerID uint   `json:"player_id" gorm:"column:player_id;index"`
+	Player   Player `json:"player,omitempty" gorm:"foreignKey:PlayerID;references:ID"`
+	Value    int    `json:"value" gorm:"column:value"` // место, число побед/матчей или рейтинг
+}
+
+type SeasonSummary struct {
+	SeasonID uint           `json:"season_id"`
+	ClosedAt *time.Time     `json:"closed_at"`
+	Results  []SeasonResult `json:"results"`
+	Awards   []SeasonAward  `json:"awards"`
+}
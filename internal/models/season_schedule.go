@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Шаблоны повторяющихся сезонов, которые планировщик создаёт сам.
+const (
+	SeasonRecurrenceMonthly   = "monthly"
+	SeasonRecurrenceQuarterly = "quarterly"
+)
+
+// Действия планировщика сезонов.
+const (
+	SchedulerActionDeactivate = "deactivate" // сезон закончился, но ещё не закрыт
+	SchedulerActionClose      = "close"
+	SchedulerActionActivate   = "activate"
+	SchedulerActionCreate     = "create"
+)
+
+type SchedulerAction struct {
+	At         time.Time `json:"at"`
+	Action     string    `json:"action"`
+	SeasonID   uint      `json:"season_id,omitempty"`
+	SeasonName string    `json:"season_name"`
+	Error      string    `json:"error,omitempty"` // действие не удалось и будет повторено; та же ошибка повторно не записывается
+}
+
+type SchedulerStatus struct {
+	Interval   string            `json:"interval"`
+	Recurrence string            `json:"recurrence,omitempty"`
+	LastRunAt  *time.Time        `json:"last_run_at,omitempty"`
+	Actions    []SchedulerAction `json:"actions"` // последние действия, новые первыми
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Алгоритмы подписи access-токенов.
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey — ключ подписи access-токенов. Новые токены подписывает неотозванный ключ
+// с самым поздним наступившим ActivatesAt. До ActivatesAt ключ уже опубликован в JWKS,
+// чтобы другие экземпляры и потребители JWKS узнали его раньше первого подписанного им токена.
+// Выведенный из оборота ключ (RetiredAt) ещё проверяет токены до ExpiresAt,
+// чтобы выданные им токены дожили до своего срока.
+type SigningKey struct {
+	KID         string     `gorm:"column:kid;type:varchar(64);primaryKey"`
+	Algorithm   string     `gorm:"column:algorithm;type:varchar(16);not null"`
+	PrivateKey  string     `gorm:"column:private_key;type:text;not null"` // PKCS#8, PEM
+	PublicKey   string     `gorm:"column:public_key;type:text;not null"`  // PKIX, PEM
+	CreatedAt   time.Time  `gorm:"column:created_at;not null"`
+	ActivatesAt time.Time  `gorm:"column:activates_at;not null"`
+	RetiredAt   *time.Time `gorm:"column:retired_at"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at;index"`
+}
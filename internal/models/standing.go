@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Standing struct {
 	gorm.Model `json:"-"`
@@ -11,8 +15,59 @@ type Standing struct {
 	SeasonID uint   `json:"season_id" gorm:"column:season_id;index:idx_standings_player_season;index" binding:"required,min=1"`
 	Season   Season `json:"season,omitempty" gorm:"foreignKey:SeasonID;references:ID"`
 
+	// Разряд таблицы: одиночки и пары считаются отдельно
+	Type string `json:"type" gorm:"column:type;type:varchar(16);default:singles;index:idx_standings_player_season"`
+
 	Wins   int `json:"wins" gorm:"column:wins" binding:"min=0"`
 	Losses int `json:"losses" gorm:"column:losses" binding:"min=0"`
 	Points int `json:"points" gorm:"column:points" binding:"min=0"`
-	Rank   int `json:"rank" gorm:"column:rank" binding:"min=0"`
+	Rank   int `json:"rank" gorm:"column:rank" binding:"min=0"` // место считается в базе по правилам сезона (см. Season.TieBreakers)
+
+	// Место на конец прошлой недели и изменение с тех пор (положительное — подъём)
+	PreviousRank *int `json:"previous_rank,omitempty" gorm:"-"`
+	Movement     int  `json:"movement" gorm:"-"`
+
+	// Сезонный рейтинг. Заводится при первом матче игрока в сезоне по политике сброса сезона
+	// и меняется только матчами этого сезона; общий рейтинг хранится в Player.Rating.
+	Rating           int     `json:"rating" gorm:"column:rating;default:0"`
+	RatingDeviation  float64 `json:"rating_deviation" gorm:"column:rating_deviation;default:0"`
+	RatingVolatility float64 `json:"rating_volatility" gorm:"column:rating_volatility;default:0"`
+}
+
+// Порядок строк турнирной таблицы.
+const (
+	StandingsOrderPoints = "points"
+	StandingsOrderRating = "rating"
+)
+
+// Критерии распределения мест. Сезон задаёт их список в порядке применения,
+// все критерии сравниваются по убыванию.
+const (
+	TieBreakPoints      = "points"       // очки
+	TieBreakWins        = "wins"         // победы
+	TieBreakWinDiff     = "win_diff"     // разница побед и поражений
+	TieBreakHeadToHead  = "head_to_head" // победы в матчах между игроками, равными по предыдущим критериям
+	TieBreakGamesRatio  = "games_ratio"  // доля выигранных партий
+	TieBreakPointsRatio = "points_ratio" // доля выигранных розыгрышей
+	TieBreakBuchholz    = "buchholz"     // сумма очков соперников (коэффициент Бухгольца)
+	TieBreakRating      = "rating"       // сезонный рейтинг
+)
+
+var TieBreakers = []string{
+	TieBreakPoints, TieBreakWins, TieBreakWinDiff, TieBreakHeadToHead,
+	TieBreakGamesRatio, TieBreakPointsRatio, TieBreakBuchholz, TieBreakRating,
+}
+
+const DefaultTieBreakers = "points,win_diff,rating"
+
+// StandingRankSnapshot — место игрока на конец недели WeekStart (последнее значение
+// за неделю). По снимкам считается движение в таблице.
+type StandingRankSnapshot struct {
+	gorm.Model `json:"-"`
+
+	SeasonID  uint      `json:"season_id" gorm:"column:season_id;uniqueIndex:idx_rank_snapshots_week"`
+	Type      string    `json:"type" gorm:"column:type;type:varchar(16);uniqueIndex:idx_rank_snapshots_week"`
+	PlayerID  uint      `json:"player_id" gorm:"column:player_id;uniqueIndex:idx_rank_snapshots_week"`
+	WeekStart time.Time `json:"week_start" gorm:"column:week_start;type:date;uniqueIndex:idx_rank_snapshots_week"`
+	Rank      int       `json:"rank" gorm:"column:rank"`
 }
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"time"
+)
+
+// LimitError — запрос отклонён: лимит исчерпан или ключ заблокирован после неудач.
+type LimitError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LimitError) Error() string {
+	if e.Locked {
+		return "too many failed attempts, try again later"
+	}
+	return "too many requests, try again later"
+}
+
+// RetryAfterSeconds — значение заголовка Retry-After, не меньше секунды.
+func (e *LimitError) RetryAfterSeconds() int {
+	return int(math.Max(1, math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// Limiter — обёртка над Store для обработчиков и сервисов. Ошибки хранилища
+// пишутся в лог, а запрос пропускается: недоступная база не должна закрывать вход.
+type Limiter struct {
+	store  Store
+	logger *slog.Logger
+}
+
+func NewLimiter(store Store, logger *slog.Logger) *Limiter {
+	return &Limiter{store: store, logger: logger}
+}
+
+// Allow забирает токен из корзины key. Нулевой лимит отключает проверку.
+func (l *Limiter) Allow(key string, limit Limit) *LimitError {
+	if limit.Burst <= 0 {
+		return nil
+	}
+
+	retryAfter, err := l.store.Take(key, limit, time.Now())
+	if err != nil {
+		l.logger.Error("ошибка хранилища лимитов, запрос пропущен", "key", key, "error", err)
+		return nil
+	}
+	if retryAfter > 0 {
+		l.logger.Warn("превышен лимит запросов", "key", key, "limit", limit.String())
+		return &LimitError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// CheckLock возвращает ошибку, если key заблокирован после серии неудач.
+func (l *Limiter) CheckLock(key string) *LimitError {
+	now := time.Now()
+
+	until, err := l.store.LockedUntil(key, now)
+	if err != nil {
+		l.logger.Error("ошибка хранилища лимитов, запрос пропущен", "key", key, "error", err)
+		return nil
+	}
+	if until != nil {
+		return &LimitError{RetryAfter: until.Sub(now), Locked: true}
+	}
+	return nil
+}
+
+// Fail засчитывает неудачу по key.
+func (l *Limiter) Fail(key string, policy LockoutPolicy) {
+	if policy.Threshold <= 0 {
+		return
+	}
+
+	until, err := l.store.Fail(key, policy, time.Now())
+	if err != nil {
+		l.logger.Error("ошибка хранилища лимитов", "key", key, "error", err)
+		return
+	}
+	if until != nil {
+		l.logger.Warn("ключ заблокирован после неудачных попыток", "key", key, "until", *until)
+	}
+}
+
+// Reset сбрасывает счётчик неудач по key после успешной попытки.
+func (l *Limiter) Reset(key string) {
+	if err := l.store.Reset(key); err != nil {
+		l.logger.Error("ошибка хранилища лимитов", "key", key, "error", err)
+	}
+}
+
+// Sweep удаляет устаревшие записи хранилища.
+func (l *Limiter) Sweep() (int, error) {
+	return l.store.Sweep(time.Now())
+}
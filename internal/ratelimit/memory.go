@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"shumnaya/internal/models"
+)
+
+// MemoryStore хранит состояние в памяти процесса. Подходит для одного экземпляра сервиса:
+// при нескольких экземплярах у каждого свои корзины, и лимиты фактически умножаются.
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*models.RateLimitBucket
+	failures map[string]*models.RateLimitFailure
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*models.RateLimitBucket),
+		failures: make(map[string]*models.RateLimitFailure),
+	}
+}
+
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = NewBucket(key, limit, now)
+		s.buckets[key] = b
+	}
+	return TakeToken(b, limit, now), nil
+}
+
+func (s *MemoryStore) Fail(key string, policy LockoutPolicy, now time.Time) (*time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.failures[key]
+	if !ok {
+		f = &models.RateLimitFailure{Key: key}
+		s.failures[key] = f
+	}
+	RegisterFailure(f, policy, now)
+	return ActiveLock(f, now), nil
+}
+
+func (s *MemoryStore) LockedUntil(key string, now time.Time) (*time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f, ok := s.failures[key]; ok {
+		return ActiveLock(f, now), nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *MemoryStore) Sweep(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for key, b := range s.buckets {
+		if !b.ExpiresAt.After(now) {
+			delete(s.buckets, key)
+			removed++
+		}
+	}
+	for key, f := range s.failures {
+		if !f.ExpiresAt.After(now) {
+			delete(s.failures, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
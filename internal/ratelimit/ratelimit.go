@@ -0,0 +1,116 @@
+// Package ratelimit ограничивает частоту запросов корзинами токенов и блокирует
+// ключи (IP, email) после серии неудачных попыток.
+//
+// Состояние хранится в Store: MemoryStore для одного экземпляра сервиса,
+// repository.NewRateLimitRepository (Postgres) — для нескольких экземпляров за балансировщиком.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"shumnaya/internal/models"
+)
+
+// Limit — Burst запросов подряд, после чего корзина пополняется на Burst запросов за Per.
+type Limit struct {
+	Burst int
+	Per   time.Duration
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Burst, l.Per)
+}
+
+// rate — токенов в секунду.
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Per.Seconds()
+}
+
+// LockoutPolicy — блокировка после Threshold неудач, между которыми прошло не больше Window.
+// Первая блокировка длится Base, каждая следующая неудача удваивает её, но не больше Max.
+type LockoutPolicy struct {
+	Threshold int
+	Window    time.Duration
+	Base      time.Duration
+	Max       time.Duration
+}
+
+// Store хранит корзины и счётчики неудач. Методы атомарны по ключу.
+type Store interface {
+	// Take забирает токен из корзины key; RetryAfter > 0, если токенов нет.
+	Take(key string, limit Limit, now time.Time) (retryAfter time.Duration, err error)
+	// Fail засчитывает неудачу и возвращает, до какого времени ключ заблокирован (nil — не заблокирован).
+	Fail(key string, policy LockoutPolicy, now time.Time) (*time.Time, error)
+	// LockedUntil возвращает время окончания действующей блокировки ключа или nil.
+	LockedUntil(key string, now time.Time) (*time.Time, error)
+	// Reset сбрасывает счётчик неудач ключа.
+	Reset(key string) error
+	// Sweep удаляет устаревшие записи и возвращает их число.
+	Sweep(now time.Time) (int, error)
+}
+
+// TakeToken пополняет корзину за прошедшее время и забирает из неё токен.
+// Общая логика для всех хранилищ: они только загружают и сохраняют запись.
+func TakeToken(b *models.RateLimitBucket, limit Limit, now time.Time) time.Duration {
+	burst := float64(limit.Burst)
+
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed*limit.rate())
+	}
+	b.UpdatedAt = now
+
+	var retryAfter time.Duration
+	if b.Tokens >= 1 {
+		b.Tokens--
+	} else {
+		retryAfter = time.Duration((1 - b.Tokens) / limit.rate() * float64(time.Second))
+	}
+
+	b.ExpiresAt = now.Add(time.Duration((burst - b.Tokens) / limit.rate() * float64(time.Second)))
+	return retryAfter
+}
+
+// NewBucket — полная корзина для нового ключа.
+func NewBucket(key string, limit Limit, now time.Time) *models.RateLimitBucket {
+	return &models.RateLimitBucket{Key: key, Tokens: float64(limit.Burst), UpdatedAt: now, ExpiresAt: now}
+}
+
+// RegisterFailure засчитывает неудачу: счётчик сбрасывается, если прошлая неудача была
+// раньше окна; с Threshold-й неудачи ключ блокируется, и каждая следующая удваивает блокировку.
+func RegisterFailure(f *models.RateLimitFailure, policy LockoutPolicy, now time.Time) {
+	if now.Sub(f.LastFailureAt) > policy.Window {
+		f.Failures = 0
+		f.LockedUntil = nil
+	}
+	f.Failures++
+	f.LastFailureAt = now
+
+	if f.Failures >= policy.Threshold {
+		lock := policy.Base
+		for i := policy.Threshold; i < f.Failures && lock < policy.Max; i++ {
+			lock *= 2
+		}
+		if lock > policy.Max {
+			lock = policy.Max
+		}
+
+		until := now.Add(lock)
+		f.LockedUntil = &until
+	}
+
+	f.ExpiresAt = now.Add(policy.Window)
+	if f.LockedUntil != nil && f.LockedUntil.After(f.ExpiresAt) {
+		f.ExpiresAt = *f.LockedUntil
+	}
+}
+
+// ActiveLock — время окончания блокировки, если она ещё действует.
+func ActiveLock(f *models.RateLimitFailure, now time.Time) *time.Time {
+	if f.LockedUntil != nil && f.LockedUntil.After(now) {
+		return f.LockedUntil
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// maxBindParams — предел числа параметров одного запроса в протоколе PostgreSQL.
+const maxBindParams = 65535
+
+// valuesList собирает список VALUES из rows одинаковых строк вида "(?::bigint, ?::bigint)"
+// для пакетных UPDATE ... FROM (VALUES ...).
+func valuesList(rows int, row string) string {
+	var b strings.Builder
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(row)
+	}
+	return b.String()
+}
+
+// rowsPerStatement — сколько строк по paramsPerRow параметров помещается в один запрос.
+func rowsPerStatement(paramsPerRow int) int {
+	return maxBindParams / paramsPerRow
+}
+
+// idChunks делит список id на части, каждая из которых помещается в один запрос "IN ?".
+func idChunks(ids []uint) [][]uint {
+	chunk := rowsPerStatement(1)
+	chunks := make([][]uint, 0, len(ids)/chunk+1)
+	for start := 0; start < len(ids); start += chunk {
+		chunks = append(chunks, ids[start:min(start+chunk, len(ids))])
+	}
+	return chunks
+}
+
+// insertBatchSize урезает размер пачки CreateInBatches, чтобы вставка строк model
+// не выходила за предел параметров запроса.
+func insertBatchSize(db *gorm.DB, model interface{}, batchSize int) (int, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+	return min(batchSize, rowsPerStatement(len(stmt.Schema.DBNames))), nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Индексы, которые не выразить тегами моделей: ключ курсора матчей включает id из gorm.Model,
+// фильтры по разнице рейтингов и месту проведения идут по выражениям, поиск игроков
+// по имени — по триграммам pg_trgm.
+var extraIndexes = []string{
+	"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+	"CREATE INDEX IF NOT EXISTS idx_matches_played_at_id ON matches (played_at, id)",
+	"CREATE INDEX IF NOT EXISTS idx_matches_rating_diff ON matches ((winner_rating_before - loser_rating_before))",
+	"CREATE INDEX IF NOT EXISTS idx_matches_venue ON matches (lower(venue))",
+	"CREATE INDEX IF NOT EXISTS idx_players_name_trgm ON players USING gin (name gin_trgm_ops)",
+}
+
+// EnsureIndexes создаёт недостающие индексы после AutoMigrate.
+func EnsureIndexes(db *gorm.DB) error {
+	for _, stmt := range extraIndexes {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/utils/cursor"
+
+	"gorm.io/gorm"
+)
+
+// LeaderboardRepository ранжирует игроков по рейтингу одним запросом: матчи и последняя
+// активность считаются агрегатом по подтверждённым матчам.
+type LeaderboardRepository interface {
+	WithDB(tx *gorm.DB) LeaderboardRepository
+
+	GetPage(filter models.LeaderboardFilter, page models.PageRequest) (*models.LeaderboardPage, error)
+	GetEntry(filter models.LeaderboardFilter, playerID uint) (*models.LeaderboardEntry, error)
+}
+
+type leaderboardRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewLeaderboardRepository(db *gorm.DB, logger *slog.Logger) LeaderboardRepository {
+	return &leaderboardRepository{db: db, logger: logger}
+}
+
+func (r *leaderboardRepository) WithDB(tx *gorm.DB) LeaderboardRepository {
+	return &leaderboardRepository{db: tx, logger: r.logger}
+}
+
+// leaderboardCursor — ключ последней строки страницы.
+type leaderboardCursor struct {
+	Rating   int  `json:"r"`
+	PlayerID uint `json:"i"`
+}
+
+// leaderboardRow — строка CTE board, которую собирает leaderboardQuery.
+type leaderboardRow struct {
+	Rank        int
+	PlayerID    uint
+	Name        string
+	Rating      int
+	Games       int
+	Wins        int
+	LastMatchAt *time.Time
+}
+
+func (row leaderboardRow) entry() models.LeaderboardEntry {
+	return models.LeaderboardEntry{
+		Rank:        row.Rank,
+		PlayerID:    row.PlayerID,
+		Name:        row.Name,
+		Rating:      row.Rating,
+		Games:       row.Games,
+		Wins:        row.Wins,
+		Losses:      row.Games - row.Wins,
+		LastMatchAt: row.LastMatchAt,
+	}
+}
+
+// leaderboardQuery собирает CTE board — игроков, прошедших фильтр, с рейтингом,
+// числом матчей и побед, датой последнего матча и местом (RANK по рейтингу).
+func leaderboardQuery(filter models.LeaderboardFilter) (string, map[string]interface{}) {
+	args := map[string]interface{}{
+		"confirmed": models.MatchStatusConfirmed,
+		"singles":   models.MatchTypeSingles,
+	}
+
+	matchConditions := []string{
+		"m.deleted_at IS NULL",
+		"m.status = @confirmed",
+		"x.player_id IS NOT NULL",
+	}
+	if filter.SeasonID != nil {
+		matchConditions = append(matchConditions, "m.season_id = @season")
+		args["season"] = *filter.SeasonID
+	}
+	if filter.Type != nil {
+		matchConditions = append(matchConditions, "COALESCE(NULLIF(m.type, ''), @singles) = @type")
+		args["type"] = *filter.Type
+	}
+
+	rating := "p.rating"
+	joins := "LEFT JOIN played pl ON pl.player_id = p.id"
+	if filter.SeasonID != nil {
+		// Сезонные рейтинги ведутся по разрядам, поэтому без разряда берётся одиночный
+		if filter.Type == nil {
+			args["type"] = models.MatchTypeSingles
+		}
+		rating = "s.rating"
+		joins = `JOIN played pl ON pl.player_id = p.id
+		JOIN standings s ON s.player_id = p.id AND s.season_id = @season AND s.type = @type AND s.deleted_at IS NULL`
+	}
+
+	playerConditions := []string{"p.deleted_at IS NULL"}
+	if filter.MinGames > 0 {
+		playerConditions = append(playerConditions, "COALESCE(pl.games, 0) >= @min_games")
+		args["min_games"] = filter.MinGames
+	}
+	if filter.ActiveSince != nil {
+		playerConditions = append(playerConditions, "pl.last_match_at >= @active_since")
+		args["active_since"] = *filter.ActiveSince
+	}
+
+	query := `
+	WITH played AS (
+		SELECT x.player_id, COUNT(*) AS games, COUNT(*) FILTER (WHERE x.won) AS wins, MAX(m.played_at) AS last_match_at
+		FROM matches m
+		CROSS JOIN LATERAL (VALUES
+			(m.winner_id, true), (m.winner_partner_id, true), (m.loser_id, false), (m.loser_partner_id, false)
+		) AS x(player_id, won)
+		WHERE ` + strings.Join(matchConditions, "\n\t\t\tAND ") + `
+		GROUP BY x.player_id
+	),
+	board AS (
+		SELECT p.id AS player_id, p.name, ` + rating + ` AS rating,
+			COALESCE(pl.games, 0) AS games,
+			COALESCE(pl.wins, 0) AS wins,
+			pl.last_match_at,
+			RANK() OVER (ORDER BY ` + rating + ` DESC) AS rank
+		FROM players p
+		` + joins + `
+		WHERE ` + strings.Join(playerConditions, "\n\t\t\tAND ") + `
+	)`
+
+	return query, args
+}
+
+// GetPage возвращает страницу рейтинг-листа по убыванию рейтинга (при равенстве — по id игрока).
+func (r *leaderboardRepository) GetPage(filter models.LeaderboardFilter, page models.PageRequest) (*models.LeaderboardPage, error) {
+	result := &models.LeaderboardPage{PageInfo: models.PageInfo{Limit: page.Limit}}
+
+	board, args := leaderboardQuery(filter)
+
+	if page.WithTotal {
+		var total int64
+		if err := r.db.Raw(board+` SELECT COUNT(*) FROM board`, args).Scan(&total).Error; err != nil {
+			r.logger.Error("ошибка подсчёта игроков рейтинг-листа", "error", err)
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	where := ""
+	if page.Cursor != "" {
+		var after leaderboardCursor
+		if err := cursor.Decode(page.Cursor, &after); err != nil {
+			return nil, cursor.ErrInvalidCursor
+		}
+		where = "WHERE board.rating < @after_rating OR (board.rating = @after_rating AND board.player_id > @after_id)"
+		args["after_rating"] = after.Rating
+		args["after_id"] = after.PlayerID
+	}
+	args["limit"] = page.Limit + 1
+
+	var rows []leaderboardRow
+	query := board + `
+	SELECT board.* FROM board
+	` + where + `
+	ORDER BY board.rating DESC, board.player_id
+	LIMIT @limit`
+
+	if err := r.db.Raw(query, args).Scan(&rows).Error; err != nil {
+		r.logger.Error("ошибка получения страницы рейтинг-листа", "error", err)
+		return nil, err
+	}
+
+	rows, result.HasMore = trimPage(rows, page.Limit)
+	result.Data = make([]models.LeaderboardEntry, len(rows))
+	for i, row := range rows {
+		result.Data[i] = row.entry()
+	}
+
+	if result.HasMore {
+		last := rows[len(rows)-1]
+		result.NextCursor = cursor.Encode(leaderboardCursor{Rating: last.Rating, PlayerID: last.PlayerID})
+	}
+
+	return result, nil
+}
+
+// GetEntry возвращает строку игрока в рейтинг-листе при тех же фильтрах
+// или gorm.ErrRecordNotFound, если игрок в лист не попадает.
+func (r *leaderboardRepository) GetEntry(filter models.LeaderboardFilter, playerID uint) (*models.LeaderboardEntry, error) {
+	board, args := leaderboardQuery(filter)
+	args["player"] = playerID
+
+	var rows []leaderboardRow
+	query := board + `
+	SELECT board.* FROM board WHERE board.player_id = @player`
+
+	if err := r.db.Raw(query, args).Scan(&rows).Error; err != nil {
+		r.logger.Error("ошибка получения места игрока в рейтинг-листе", "player_id", playerID, "error", err)
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	entry := rows[0].entry()
+	return &entry, nil
+}
@@ -2,26 +2,45 @@ package repository
 
 import (
 	"log/slog"
+	"time"
+
 	"shumnaya/internal/models"
+	"shumnaya/internal/utils/cursor"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ledgerLock — ключ advisory-блокировки рейтингов и таблиц: подтверждения, исправления,
+// аннулирования и пересчёт перезаписывают одни и те же строки игроков и таблиц
+// и должны идти строго по очереди.
+const ledgerLock = 7305002
+
 type MatchRepository interface {
 	WithDB(tx *gorm.DB) MatchRepository
 	Create(match *models.Match) error
+	Update(match *models.Match) error
+	ReplaceGames(matchID uint, games []models.MatchGame) error
+	Delete(id uint) error
 
 	Get() ([]models.Match, error)
 	GetByID(id uint) (*models.Match, error)
+	GetByIDForUpdate(id uint) (*models.Match, error)
+	LockLedger() error
 
 	GetBySeasonID(seasonID uint) ([]models.Match, error)
 	GetByPlayerID(playerID uint) ([]models.Match, error)
-	GetRecentByPlayerID(playerID uint, limit int) ([]models.Match, error)
-
-	GetFiltered(filter *models.MatchFilter) ([]models.Match, error)
-	HeadToHeadRecordMatchesCount(playerAID, playerBID uint) (countA int64, countB int64, countC int64, err error)
-
-	HeadToHeadRecentMatches(playerAID, playerBID uint, limit int) ([]models.Match, error)
+	CountPlayerResults(playerID uint) (map[string]models.MatchSummary, error)
+	GetRecentByPlayerID(playerID uint, matchType string, limit int) ([]models.Match, error)
+	GetPlayedSince(playedAt time.Time, id uint) ([]models.Match, error)
+	GetPendingBefore(playedAt time.Time) ([]models.Match, error)
+	GetPendingBySeason(seasonID uint) ([]models.Match, error)
+	GetConfirmedPage(afterPlayedAt time.Time, afterID uint, limit int) ([]models.Match, error)
+	UpdateRatingChanges(matches []models.Match) error
+
+	GetFiltered(filter *models.MatchFilter, page models.PageRequest) (*models.MatchPage, error)
+	CountBySeasonAndStatus(seasonID uint, status string) (int64, error)
+	HeadToHeadRecentMatches(playerAID, playerBID uint, matchType string, limit int) ([]models.Match, error)
 }
 
 type matchRepository struct {
@@ -37,29 +56,186 @@ func (r *matchRepository) WithDB(tx *gorm.DB) MatchRepository {
 	return &matchRepository{db: tx, log: r.log}
 }
 
-func (r *matchRepository) GetFiltered(filter *models.MatchFilter) ([]models.Match, error) {
+// matchCursor — ключ последнего матча страницы.
+type matchCursor struct {
+	Sort     string    `json:"s"`
+	PlayedAt time.Time `json:"p"`
+	ID       uint      `json:"i"`
+}
+
+// GetFiltered возвращает страницу матчей по фильтру с сортировкой по (played_at, id).
+// Следующая страница начинается строго после ключа из курсора, поэтому новые матчи
+// не сдвигают уже выданные страницы.
+func (r *matchRepository) GetFiltered(filter *models.MatchFilter, page models.PageRequest) (*models.MatchPage, error) {
+	result := &models.MatchPage{PageInfo: models.PageInfo{Limit: page.Limit}}
+
+	if page.WithTotal {
+		var total int64
+		if err := r.db.Model(&models.Match{}).Scopes(matchFilter(filter)).Count(&total).Error; err != nil {
+			r.log.Error("ошибка подсчёта матчей", "error", err)
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	dir, op := sortDirection(page.Sort)
+	query := r.db.Model(&models.Match{}).Scopes(matchFilter(filter))
+
+	if page.Cursor != "" {
+		var after matchCursor
+		if err := cursor.Decode(page.Cursor, &after); err != nil || after.Sort != page.Sort {
+			return nil, cursor.ErrInvalidCursor
+		}
+		query = query.Where("(played_at, id) "+op+" (?, ?)", after.PlayedAt, after.ID)
+	}
+
 	var matches []models.Match
+	err := query.Scopes(preloadGames).
+		Order("played_at " + dir).
+		Order("id " + dir).
+		Limit(page.Limit + 1).
+		Find(&matches).Error
+	if err != nil {
+		r.log.Error("ошибка получения страницы матчей", "error", err)
+		return nil, err
+	}
+
+	result.Data, result.HasMore = trimPage(matches, page.Limit)
+	if result.HasMore {
+		last := result.Data[len(result.Data)-1]
+		result.NextCursor = cursor.Encode(matchCursor{Sort: page.Sort, PlayedAt: last.PlayedAt, ID: last.ID})
+	}
+
+	return result, nil
+}
+
+// matchFilter применяет фильтры списка матчей.
+func matchFilter(filter *models.MatchFilter) func(db *gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if filter.SeasonID != nil {
+			query = query.Where("season_id = ?", *filter.SeasonID)
+		}
+
+		if filter.Status != nil {
+			query = query.Where("status = ?", *filter.Status)
+		}
+
+		if filter.Type != nil {
+			query = query.Where("type = ?", *filter.Type)
+		}
+
+		if filter.PlayerID != nil {
+			query = query.Scopes(withPlayerResult(*filter.PlayerID, filter.OpponentID, filter.Result))
+		}
+
+		if filter.FromDate != nil {
+			query = query.Where("played_at >= ?", *filter.FromDate)
+		}
+
+		if filter.ToDate != nil {
+			query = query.Where("played_at <= ?", *filter.ToDate)
+		}
+
+		if filter.Venue != nil {
+			query = query.Where("lower(venue) = lower(?)", *filter.Venue)
+		}
+
+		if filter.WinnerGames != nil {
+			query = query.Where("winner_games = ?", *filter.WinnerGames)
+		}
 
-	query := r.db.Model(&matches)
+		if filter.LoserGames != nil {
+			query = query.Where("loser_games = ?", *filter.LoserGames)
+		}
 
-	if filter.SeasonID != nil {
-		query = query.Where("season_id = ?", *filter.SeasonID)
+		// Выражение совпадает с индексом idx_matches_rating_diff
+		if filter.MinRatingDiff != nil {
+			query = query.Where("(winner_rating_before - loser_rating_before) >= ?", *filter.MinRatingDiff)
+		}
+
+		if filter.MaxRatingDiff != nil {
+			query = query.Where("(winner_rating_before - loser_rating_before) <= ?", *filter.MaxRatingDiff)
+		}
+
+		if filter.UpsetsOnly {
+			query = query.Where("(winner_rating_before - loser_rating_before) < 0 AND NOT forfeit")
+		}
+
+		return query
 	}
+}
 
-	if filter.PlayerID != nil {
-		query = query.Where("winner_id = ? OR loser_id = ?", *filter.PlayerID, *filter.PlayerID)
+// withPlayerResult отбирает матчи игрока, при необходимости только выигранные или
+// проигранные им и только против соперника opponentID (с любой стороны пары).
+func withPlayerResult(playerID uint, opponentID *uint, result *string) func(db *gorm.DB) *gorm.DB {
+	if opponentID == nil && result == nil {
+		return withPlayer(playerID)
 	}
 
-	if filter.FromDate != nil {
-		query = query.Where("played_at >= ?", *filter.FromDate)
+	return func(db *gorm.DB) *gorm.DB {
+		won := db.Session(&gorm.Session{NewDB: true}).Where("winner_id = ? OR winner_partner_id = ?", playerID, playerID)
+		lost := db.Session(&gorm.Session{NewDB: true}).Where("loser_id = ? OR loser_partner_id = ?", playerID, playerID)
+
+		if opponentID != nil {
+			won = won.Where("loser_id = ? OR loser_partner_id = ?", *opponentID, *opponentID)
+			lost = lost.Where("winner_id = ? OR winner_partner_id = ?", *opponentID, *opponentID)
+		}
+
+		switch {
+		case result != nil && *result == models.MatchResultWon:
+			return db.Where(won)
+		case result != nil && *result == models.MatchResultLost:
+			return db.Where(lost)
+		default:
+			return db.Where(won.Or(lost))
+		}
 	}
+}
+
+func (r *matchRepository) CountBySeasonAndStatus(seasonID uint, status string) (int64, error) {
+	var count int64
 
-	if filter.ToDate != nil {
-		query = query.Where("played_at <= ?", *filter.ToDate)
+	if err := r.db.Model(&models.Match{}).Where("season_id = ? AND status = ?", seasonID, status).Count(&count).Error; err != nil {
+		r.log.Error("ошибка подсчёта матчей сезона", "season_id", seasonID, "status", status, "error", err)
+		return 0, err
 	}
+	return count, nil
+}
 
-	err := query.Find(&matches).Error
-	return matches, err
+// withPlayer отбирает матчи игрока с любой стороны, включая партнёров в парных матчах.
+func withPlayer(playerID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("winner_id = ? OR loser_id = ? OR winner_partner_id = ? OR loser_partner_id = ?",
+			playerID, playerID, playerID, playerID)
+	}
+}
+
+// headToHeadCondition отбирает подтверждённые матчи разряда @type, в которых игроки @a и @b
+// играли на разных сторонах, в том числе в составе пар. Первые два условия дают
+// планировщику индексы по участникам, последнее оставляет только встречи на разных сторонах.
+const headToHeadCondition = `deleted_at IS NULL
+	AND status = @confirmed
+	AND COALESCE(NULLIF(type, ''), @singles) = @type
+	AND (winner_id IN (@a, @b) OR winner_partner_id IN (@a, @b))
+	AND (loser_id IN (@a, @b) OR loser_partner_id IN (@a, @b))
+	AND ((@a IN (winner_id, winner_partner_id) AND @b IN (loser_id, loser_partner_id))
+		OR (@b IN (winner_id, winner_partner_id) AND @a IN (loser_id, loser_partner_id)))`
+
+func headToHeadArgs(playerAID, playerBID uint, matchType string) map[string]interface{} {
+	return map[string]interface{}{
+		"a":         playerAID,
+		"b":         playerBID,
+		"type":      matchType,
+		"confirmed": models.MatchStatusConfirmed,
+		"singles":   models.MatchTypeSingles,
+	}
+}
+
+// preloadGames подгружает счёт по партиям в порядке их номеров.
+func preloadGames(db *gorm.DB) *gorm.DB {
+	return db.Preload("Games", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number ASC")
+	})
 }
 
 func (r *matchRepository) Get() ([]models.Match, error) {
@@ -75,82 +251,269 @@ func (r *matchRepository) Create(match *models.Match) error {
 	return r.db.Create(match).Error
 }
 
-func (r *matchRepository) GetByID(id uint) (*models.Match, error) {
-	var m models.Match
-	if err := r.db.Preload("Winner").Preload("Loser").Preload("Season").First(&m, id).Error; err != nil {
+// Update сохраняет поля матча без связанных сущностей (игроков, сезона, партий).
+func (r *matchRepository) Update(match *models.Match) error {
+	if err := r.db.Omit(clause.Associations).Save(match).Error; err != nil {
+		r.log.Error("ошибка обновления матча", "match_id", match.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// ReplaceGames заменяет счёт по партиям матча.
+func (r *matchRepository) ReplaceGames(matchID uint, games []models.MatchGame) error {
+	if err := r.db.Unscoped().Where("match_id = ?", matchID).Delete(&models.MatchGame{}).Error; err != nil {
+		r.log.Error("ошибка удаления партий матча", "match_id", matchID, "error", err)
+		return err
+	}
+
+	if len(games) == 0 {
+		return nil
+	}
+
+	for i := range games {
+		games[i].MatchID = matchID
+	}
+
+	if err := r.db.Create(&games).Error; err != nil {
+		r.log.Error("ошибка сохранения партий матча", "match_id", matchID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *matchRepository) Delete(id uint) error {
+	if err := r.db.Delete(&models.Match{}, id).Error; err != nil {
+		r.log.Error("ошибка удаления матча", "match_id", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetPlayedSince возвращает подтверждённые матчи начиная с указанного (включительно)
+// в хронологическом порядке проведения: по played_at, при равенстве — по id.
+func (r *matchRepository) GetPlayedSince(playedAt time.Time, id uint) ([]models.Match, error) {
+	var matches []models.Match
+
+	err := r.db.
+		Where("status = ?", models.MatchStatusConfirmed).
+		Where("played_at > ? OR (played_at = ? AND id >= ?)", playedAt, playedAt, id).
+		Order("played_at ASC, id ASC").
+		Find(&matches).Error
+	if err != nil {
+		r.log.Error("ошибка получения матчей для пересчёта", "played_at", playedAt, "match_id", id, "error", err)
 		return nil, err
 	}
-	return &m, nil
+
+	return matches, nil
 }
 
-func (r *matchRepository) GetBySeasonID(seasonID uint) ([]models.Match, error) {
+// GetConfirmedPage возвращает следующую страницу подтверждённых матчей в хронологическом
+// порядке после матча (afterPlayedAt, afterID). Загружаются только поля, нужные для
+// расчёта рейтинга, чтобы пересчёт всей истории не держал в памяти лишнее.
+func (r *matchRepository) GetConfirmedPage(afterPlayedAt time.Time, afterID uint, limit int) ([]models.Match, error) {
 	var matches []models.Match
-	if err := r.db.Where("season_id = ?", seasonID).
 
-		Find(&matches).Error; err != nil {
+	err := r.db.
+		Select("id", "winner_id", "loser_id", "winner_partner_id", "loser_partner_id", "season_id", "type", "played_at",
+			"forfeit", "winner_games", "loser_games",
+			"winner_rating_change", "loser_rating_change", "winner_partner_rating_change", "loser_partner_rating_change",
+			"rating_engine", "winner_win_probability", "winner_standing_points", "loser_standing_points",
+			"winner_rating_before", "loser_rating_before").
+		Where("status = ?", models.MatchStatusConfirmed).
+		Where("played_at > ? OR (played_at = ? AND id > ?)", afterPlayedAt, afterPlayedAt, afterID).
+		Order("played_at ASC, id ASC").
+		Limit(limit).
+		Find(&matches).Error
+	if err != nil {
+		r.log.Error("ошибка получения страницы матчей", "after_match_id", afterID, "error", err)
 		return nil, err
 	}
+
 	return matches, nil
 }
 
-func (r *matchRepository) GetByPlayerID(playerID uint) ([]models.Match, error) {
+// matchRatingParams — число параметров одной строки в UpdateRatingChanges.
+const matchRatingParams = 11
+
+// UpdateRatingChanges сохраняет посчитанные изменения рейтинга матчей, рейтинги сторон
+// перед матчем и начисленные за матч очки в таблицу — одним запросом на каждую пачку, помещающуюся в предел параметров.
+func (r *matchRepository) UpdateRatingChanges(matches []models.Match) error {
+	chunk := rowsPerStatement(matchRatingParams)
+	for start := 0; start < len(matches); start += chunk {
+		if err := r.updateRatingChanges(matches[start:min(start+chunk, len(matches))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *matchRepository) updateRatingChanges(matches []models.Match) error {
+	args := make([]interface{}, 0, len(matches)*matchRatingParams)
+	for _, m := range matches {
+		args = append(args, m.ID, m.WinnerRatingChange, m.LoserRatingChange, m.WinnerPartnerRatingChange,
+			m.LoserPartnerRatingChange, m.RatingEngine, m.WinnerWinProbability, m.WinnerStandingPoints, m.LoserStandingPoints,
+			m.WinnerRatingBefore, m.LoserRatingBefore)
+	}
+
+	query := `UPDATE matches AS m SET
+			winner_rating_change = v.winner_change,
+			loser_rating_change = v.loser_change,
+			winner_partner_rating_change = v.winner_partner_change,
+			loser_partner_rating_change = v.loser_partner_change,
+			rating_engine = v.engine,
+			winner_win_probability = v.probability,
+			winner_standing_points = v.winner_points,
+			loser_standing_points = v.loser_points,
+			winner_rating_before = v.winner_before,
+			loser_rating_before = v.loser_before
+		FROM (VALUES ` + valuesList(len(matches), "(?::bigint, ?::bigint, ?::bigint, ?::bigint, ?::bigint, ?::text, ?::double precision, ?::bigint, ?::bigint, ?::bigint, ?::bigint)") + `)
+			AS v(id, winner_change, loser_change, winner_partner_change, loser_partner_change, engine, probability,
+				winner_points, loser_points, winner_before, loser_before)
+		WHERE m.id = v.id`
+
+	if err := r.db.Exec(query, args...).Error; err != nil {
+		r.log.Error("ошибка пакетного обновления рейтинга матчей", "count", len(matches), "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetPendingBefore возвращает неподтверждённые матчи, заявленные раньше playedAt.
+func (r *matchRepository) GetPendingBefore(playedAt time.Time) ([]models.Match, error) {
 	var matches []models.Match
-	if err := r.db.Where("winner_id = ? OR loser_id = ?", playerID, playerID).
 
-		Find(&matches).Error; err != nil {
+	err := r.db.
+		Where("status = ? AND played_at < ?", models.MatchStatusPending, playedAt).
+		Order("played_at ASC, id ASC").
+		Find(&matches).Error
+	if err != nil {
+		r.log.Error("ошибка получения неподтверждённых матчей", "error", err)
 		return nil, err
 	}
+
 	return matches, nil
 }
 
-func (r *matchRepository) GetRecentByPlayerID(playerID uint, limit int) ([]models.Match, error) {
+// GetPendingBySeason возвращает неподтверждённые матчи сезона в хронологическом порядке.
+func (r *matchRepository) GetPendingBySeason(seasonID uint) ([]models.Match, error) {
 	var matches []models.Match
-	if err := r.db.Where("winner_id = ? OR loser_id = ?", playerID, playerID).
 
-		Order("played_at DESC").
-		Limit(limit).
+	err := r.db.
+		Where("status = ? AND season_id = ?", models.MatchStatusPending, seasonID).
+		Order("played_at ASC, id ASC").
+		Find(&matches).Error
+	if err != nil {
+		r.log.Error("ошибка получения неподтверждённых матчей сезона", "season_id", seasonID, "error", err)
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (r *matchRepository) GetByID(id uint) (*models.Match, error) {
+	var m models.Match
+	if err := r.db.Preload("Winner").Preload("Loser").Preload("Season").Scopes(preloadGames).First(&m, id).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetByIDForUpdate блокирует строку матча до конца транзакции и возвращает его свежую копию:
+// подтверждение, оспаривание и исправление одного матча выполняются строго по очереди.
+func (r *matchRepository) GetByIDForUpdate(id uint) (*models.Match, error) {
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Match{}, id).Error; err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// LockLedger берёт advisory-блокировку рейтингов и таблиц до конца транзакции. Берётся
+// первой в транзакции, до блокировок строк матчей, иначе две транзакции могут ждать друг друга.
+func (r *matchRepository) LockLedger() error {
+	return r.db.Exec("SELECT pg_advisory_xact_lock(?)", ledgerLock).Error
+}
+
+func (r *matchRepository) GetBySeasonID(seasonID uint) ([]models.Match, error) {
+	var matches []models.Match
+	if err := r.db.Where("season_id = ?", seasonID).
 		Find(&matches).Error; err != nil {
 		return nil, err
 	}
 	return matches, nil
 }
 
-func (r *matchRepository) HeadToHeadRecordMatchesCount(playerAID, playerBID uint) ( countA int64, countB int64, countC int64, err error) {
-	var count int64
+func (r *matchRepository) GetByPlayerID(playerID uint) ([]models.Match, error) {
+	var matches []models.Match
+	if err := r.db.Scopes(withPlayer(playerID)).
+		Where("status = ?", models.MatchStatusConfirmed).
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
 
-	if err := r.db.Model(&models.Match{}).
-		Where("(winner_id = ? AND loser_id = ?) OR (winner_id = ? AND loser_id = ?)", playerAID, playerBID, playerBID, playerAID).
-		Count(&count).Error; err != nil {
-		r.log.Error("ошибка получения записи матча между игроками", "error", err)
-		return 0, 0, 0, err
+// CountPlayerResults считает подтверждённые матчи, победы и поражения игрока по разрядам.
+func (r *matchRepository) CountPlayerResults(playerID uint) (map[string]models.MatchSummary, error) {
+	var rows []struct {
+		Type         string
+		TotalMatches int
+		Wins         int
 	}
 
-	var count1 int64
+	err := r.db.Model(&models.Match{}).
+		Select("COALESCE(NULLIF(type, ''), ?) AS type, COUNT(*) AS total_matches, "+
+			"COUNT(*) FILTER (WHERE winner_id = ? OR winner_partner_id = ?) AS wins",
+			models.MatchTypeSingles, playerID, playerID).
+		Scopes(withPlayer(playerID)).
+		Where("status = ?", models.MatchStatusConfirmed).
+		Group("1").
+		Scan(&rows).Error
+	if err != nil {
+		r.log.Error("ошибка подсчёта результатов игрока", "player_id", playerID, "error", err)
+		return nil, err
+	}
 
-	if err := r.db.Model(&models.Match{}).
-		Where("winner_id = ? AND loser_id = ?", playerAID, playerBID).
-		Count(&count1).Error; err != nil {
-		r.log.Error("ошибка получения количества побед между игроками", "error", err)
-		return 0, 0, 0, err
+	summaries := make(map[string]models.MatchSummary, len(rows))
+	for _, row := range rows {
+		summaries[row.Type] = models.MatchSummary{
+			TotalMatches: row.TotalMatches,
+			Wins:         row.Wins,
+			Losses:       row.TotalMatches - row.Wins,
+		}
 	}
+	return summaries, nil
+}
 
-	var count2 int64
+// GetRecentByPlayerID возвращает последние подтверждённые матчи игрока; пустой matchType — матчи любого разряда.
+func (r *matchRepository) GetRecentByPlayerID(playerID uint, matchType string, limit int) ([]models.Match, error) {
+	var matches []models.Match
 
-	if err := r.db.Model(&models.Match{}).
-		Where("winner_id = ? AND loser_id = ?", playerBID, playerAID).
-		Count(&count2).Error; err != nil {
-		r.log.Error("ошибка получения количества побед между игроками", "error", err)
-		return 0, 0, 0, err
+	query := r.db.Scopes(withPlayer(playerID)).
+		Where("status = ?", models.MatchStatusConfirmed)
+	if matchType != "" {
+		query = query.Where("type = ?", matchType)
 	}
 
-	return count, count1, count2, nil
+	if err := query.
+		Order("played_at DESC").
+		Limit(limit).
+		Scopes(preloadGames).
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
 }
 
-func (r *matchRepository) HeadToHeadRecentMatches(playerAID, playerBID uint, limit int) ([]models.Match, error) {
+// HeadToHeadRecentMatches возвращает последние встречи игроков a и b разряда matchType.
+func (r *matchRepository) HeadToHeadRecentMatches(playerAID, playerBID uint, matchType string, limit int) ([]models.Match, error) {
 	var matches []models.Match
 	if err := r.db.Model(&models.Match{}).
-		Where("(winner_id = ? AND loser_id = ?) OR (winner_id = ? AND loser_id = ?)", playerAID, playerBID, playerBID, playerAID).
-		Preload("Winner").Preload("Loser").Order("played_at DESC").Limit(limit).Find(&matches).Error; err != nil {
+		Where(headToHeadCondition, headToHeadArgs(playerAID, playerBID, matchType)).
+		Preload("Winner").Preload("Loser").Scopes(preloadGames).
+		Order("played_at DESC, id DESC").
+		Limit(limit).
+		Find(&matches).Error; err != nil {
 		r.log.Error("ошибка получения последних матчей между игроками", "error", err)
 		return nil, err
 	}
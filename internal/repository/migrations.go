@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"shumnaya/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PrepareMigrations приводит данные старых баз к ограничениям и значениям новых моделей;
+// вызывается до AutoMigrate.
+func PrepareMigrations(db *gorm.DB) error {
+	if err := deactivateExtraSeasons(db); err != nil {
+		return err
+	}
+	if err := addLegacyStandingPoints(db); err != nil {
+		return err
+	}
+	if err := addLegacyEmailVerification(db); err != nil {
+		return err
+	}
+	return addSigningKeyActivation(db)
+}
+
+// addLegacyEmailVerification добавляет email_verified и отмечает подтверждёнными
+// уже зарегистрированных игроков: без подтверждения нельзя записывать матчи, а письма
+// со ссылкой они не получали. Новые игроки по-прежнему начинают неподтверждёнными.
+func addLegacyEmailVerification(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Player{}) || db.Migrator().HasColumn(&models.Player{}, "email_verified") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE players ADD COLUMN email_verified boolean NOT NULL DEFAULT TRUE").Error; err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE players ALTER COLUMN email_verified SET DEFAULT FALSE").Error
+	})
+}
+
+// addSigningKeyActivation добавляет activates_at уже выпущенным ключам подписи:
+// они подписывали токены с момента выпуска.
+func addSigningKeyActivation(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.SigningKey{}) || db.Migrator().HasColumn(&models.SigningKey{}, "activates_at") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE signing_keys ADD COLUMN activates_at timestamptz").Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE signing_keys SET activates_at = created_at").Error
+	})
+}
+
+// addLegacyStandingPoints добавляет winner_standing_points со значением 1 для уже
+// записанных матчей: до появления схем очков победа приносила 1 очко. У поля модели
+// нет default, иначе GORM не записал бы посчитанные 0 очков.
+func addLegacyStandingPoints(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Match{}) || db.Migrator().HasColumn(&models.Match{}, "winner_standing_points") {
+		return nil
+	}
+	return db.Exec("ALTER TABLE matches ADD COLUMN winner_standing_points bigint DEFAULT 1").Error
+}
+
+// deactivateExtraSeasons оставляет активным один сезон: раньше активным создавался
+// каждый сезон, и уникальный индекс idx_seasons_single_active не построился бы.
+// Активным остаётся сезон, идущий сейчас, а если такого нет — начавшийся последним.
+func deactivateExtraSeasons(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Season{}) {
+		return nil
+	}
+
+	return db.Exec(`
+		UPDATE seasons SET is_active = FALSE
+		WHERE is_active AND deleted_at IS NULL
+			AND id <> (
+				SELECT id FROM seasons
+				WHERE is_active AND deleted_at IS NULL
+				ORDER BY (start_date <= NOW() AND end_date >= NOW()) DESC, start_date DESC, id DESC
+				LIMIT 1
+			)`).Error
+}
@@ -0,0 +1,22 @@
+package repository
+
+// trimPage отрезает лишнюю строку, которую запрашивают сверх limit, чтобы узнать,
+// есть ли следующая страница.
+func trimPage[T any](rows []T, limit int) ([]T, bool) {
+	if len(rows) > limit {
+		return rows[:limit], true
+	}
+	if rows == nil {
+		rows = []T{}
+	}
+	return rows, false
+}
+
+// sortDirection возвращает направление SQL-сортировки и оператор сравнения ключа
+// курсора для сортировки вида "field" / "-field".
+func sortDirection(sort string) (dir, op string) {
+	if len(sort) > 0 && sort[0] == '-' {
+		return "DESC", "<"
+	}
+	return "ASC", ">"
+}
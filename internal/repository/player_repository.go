@@ -2,19 +2,31 @@ package repository
 
 import (
 	"log/slog"
+	"strings"
+	"time"
+
 	"shumnaya/internal/models"
+	"shumnaya/internal/utils/cursor"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PlayerRepository interface {
-    WithDB(tx *gorm.DB) PlayerRepository
+	WithDB(tx *gorm.DB) PlayerRepository
 	Create(player *models.Player) error
 
 	GetByID(id uint) (*models.Player, error)
+	GetByIDForUpdate(id uint) (*models.Player, error)
 	GetByEmail(email string) (*models.Player, error)
+	GetAllRatings() ([]models.Player, error)
+	Search(query string, page models.PageRequest) (*models.PlayerSummaryPage, error)
 
 	Update(player *models.Player) error
+	UpdateRatings(players []*models.Player) error
+	UpdateRole(id uint, role string) error
+	UpdatePassword(id uint, passwordHash string) error
+	MarkEmailVerified(id uint) error
 	Delete(id uint) error
 }
 
@@ -43,6 +55,16 @@ func (r *playerRepository) GetByID(id uint) (*models.Player, error) {
 	return &player, nil
 }
 
+// GetByIDForUpdate блокирует строку игрока до конца транзакции, чтобы одну ссылку
+// из письма нельзя было использовать дважды параллельными запросами.
+func (r *playerRepository) GetByIDForUpdate(id uint) (*models.Player, error) {
+	var player models.Player
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&player, id).Error; err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
 func (r *playerRepository) GetByEmail(email string) (*models.Player, error) {
 	var player models.Player
 
@@ -55,6 +77,23 @@ func (r *playerRepository) GetByEmail(email string) (*models.Player, error) {
 	return &player, nil
 }
 
+// GetAllRatings возвращает рейтинги всех игроков, включая удалённых: их матчи
+// остаются в истории и участвуют в пересчёте.
+func (r *playerRepository) GetAllRatings() ([]models.Player, error) {
+	var players []models.Player
+
+	err := r.db.Unscoped().
+		Select("id", "name", "rating", "rating_deviation", "rating_volatility").
+		Order("id ASC").
+		Find(&players).Error
+	if err != nil {
+		r.logger.Error("ошибка получения рейтингов игроков", "error", err)
+		return nil, err
+	}
+
+	return players, nil
+}
+
 func (r *playerRepository) Create(player *models.Player) error {
 	err := r.db.Create(player).Error
 	if err != nil {
@@ -73,6 +112,82 @@ func (r *playerRepository) Update(player *models.Player) error {
 	return nil
 }
 
+// playerRatingParams — число параметров одной строки в UpdateRatings.
+const playerRatingParams = 4
+
+// UpdateRatings сохраняет рейтинги переданных игроков — одним запросом на каждую пачку,
+// помещающуюся в предел параметров. Остальные столбцы игрока не трогаются.
+func (r *playerRepository) UpdateRatings(players []*models.Player) error {
+	chunk := rowsPerStatement(playerRatingParams)
+	for start := 0; start < len(players); start += chunk {
+		if err := r.updateRatings(players[start:min(start+chunk, len(players))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *playerRepository) updateRatings(players []*models.Player) error {
+	args := make([]interface{}, 0, len(players)*playerRatingParams)
+	for _, p := range players {
+		args = append(args, p.ID, p.Rating, p.RatingDeviation, p.RatingVolatility)
+	}
+
+	query := `UPDATE players AS p SET
+			rating = v.rating,
+			rating_deviation = v.deviation,
+			rating_volatility = v.volatility,
+			updated_at = NOW()
+		FROM (VALUES ` + valuesList(len(players), "(?::bigint, ?::bigint, ?::double precision, ?::double precision)") + `)
+			AS v(id, rating, deviation, volatility)
+		WHERE p.id = v.id`
+
+	if err := r.db.Exec(query, args...).Error; err != nil {
+		r.logger.Error("ошибка пакетного обновления рейтингов игроков", "count", len(players), "error", err)
+		return err
+	}
+	return nil
+}
+
+// UpdatePassword заменяет хеш пароля игрока; gorm.ErrRecordNotFound, если игрока нет.
+func (r *playerRepository) UpdatePassword(id uint, passwordHash string) error {
+	result := r.db.Model(&models.Player{}).Where("id = ?", id).Update("password_hash", passwordHash)
+	if result.Error != nil {
+		r.logger.Error("ошибка обновления пароля игрока", "id", id, "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkEmailVerified отмечает email игрока подтверждённым; gorm.ErrRecordNotFound, если игрока нет.
+func (r *playerRepository) MarkEmailVerified(id uint) error {
+	result := r.db.Model(&models.Player{}).Where("id = ?", id).Update("email_verified", true)
+	if result.Error != nil {
+		r.logger.Error("ошибка подтверждения email игрока", "id", id, "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateRole меняет роль игрока; gorm.ErrRecordNotFound, если игрока нет.
+func (r *playerRepository) UpdateRole(id uint, role string) error {
+	result := r.db.Model(&models.Player{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		r.logger.Error("ошибка обновления роли игрока", "id", id, "role", role, "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *playerRepository) Delete(id uint) error {
 	err := r.db.Delete(&models.Player{}, id).Error
 	if err != nil {
@@ -82,3 +197,132 @@ func (r *playerRepository) Delete(id uint) error {
 
 	return nil
 }
+
+// playerCursor — ключ последнего игрока страницы; сравнивается поле, по которому идёт сортировка Sort.
+type playerCursor struct {
+	Sort       string    `json:"s"`
+	Name       string    `json:"n"`
+	Rating     int       `json:"r"`
+	LastActive time.Time `json:"a"`
+	ID         uint      `json:"i"`
+}
+
+// playerSortKeys — столбец CTE directory, по которому сортируется каждый вид списка.
+var playerSortKeys = map[string]string{
+	models.PlayerSortNameAsc:        "name_key",
+	models.PlayerSortNameDesc:       "name_key",
+	models.PlayerSortRatingAsc:      "rating",
+	models.PlayerSortRatingDesc:     "rating",
+	models.PlayerSortLastActiveAsc:  "active_key",
+	models.PlayerSortLastActiveDesc: "active_key",
+}
+
+// neverPlayed — ключ сортировки по активности для игроков без подтверждённых матчей.
+var neverPlayed = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search возвращает страницу игроков, имя которых начинается с query или похоже
+// на него по триграммам (pg_trgm), с сортировкой page.Sort и дополнительно по id.
+// Пустой query — все игроки.
+func (r *playerRepository) Search(query string, page models.PageRequest) (*models.PlayerSummaryPage, error) {
+	result := &models.PlayerSummaryPage{PageInfo: models.PageInfo{Limit: page.Limit}}
+
+	key, ok := playerSortKeys[page.Sort]
+	if !ok {
+		page.Sort, key = models.PlayerSortNameAsc, playerSortKeys[models.PlayerSortNameAsc]
+	}
+	dir, op := sortDirection(page.Sort)
+
+	args := map[string]interface{}{
+		"confirmed": models.MatchStatusConfirmed,
+		"never":     neverPlayed,
+	}
+
+	search := ""
+	if query != "" {
+		search = "AND (p.name ILIKE @prefix OR @query <% p.name)"
+		args["prefix"] = likeEscaper.Replace(query) + "%"
+		args["query"] = query
+	}
+
+	directory := `
+	WITH activity AS (
+		SELECT x.player_id, MAX(m.played_at) AS last_match_at
+		FROM matches m
+		CROSS JOIN LATERAL (VALUES (m.winner_id), (m.winner_partner_id), (m.loser_id), (m.loser_partner_id)) AS x(player_id)
+		WHERE m.deleted_at IS NULL AND m.status = @confirmed AND x.player_id IS NOT NULL
+		GROUP BY x.player_id
+	),
+	directory AS (
+		SELECT p.id, p.name, p.rating, a.last_match_at,
+			lower(p.name) AS name_key,
+			COALESCE(a.last_match_at, @never) AS active_key
+		FROM players p
+		LEFT JOIN activity a ON a.player_id = p.id
+		WHERE p.deleted_at IS NULL ` + search + `
+	)`
+
+	if page.WithTotal {
+		var total int64
+		if err := r.db.Raw(directory+` SELECT COUNT(*) FROM directory`, args).Scan(&total).Error; err != nil {
+			r.logger.Error("ошибка подсчёта игроков", "query", query, "error", err)
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	where := ""
+	if page.Cursor != "" {
+		var after playerCursor
+		if err := cursor.Decode(page.Cursor, &after); err != nil || after.Sort != page.Sort {
+			return nil, cursor.ErrInvalidCursor
+		}
+		where = "WHERE (" + key + ", id) " + op + " (@after_key, @after_id)"
+		args["after_id"] = after.ID
+		switch key {
+		case "name_key":
+			args["after_key"] = after.Name
+		case "rating":
+			args["after_key"] = after.Rating
+		default:
+			args["after_key"] = after.LastActive
+		}
+	}
+	args["limit"] = page.Limit + 1
+
+	var rows []struct {
+		models.PlayerSummary
+		NameKey   string
+		ActiveKey time.Time
+	}
+	sql := directory + `
+	SELECT * FROM directory
+	` + where + `
+	ORDER BY ` + key + ` ` + dir + `, id ` + dir + `
+	LIMIT @limit`
+
+	if err := r.db.Raw(sql, args).Scan(&rows).Error; err != nil {
+		r.logger.Error("ошибка поиска игроков", "query", query, "error", err)
+		return nil, err
+	}
+
+	rows, result.HasMore = trimPage(rows, page.Limit)
+	result.Data = make([]models.PlayerSummary, len(rows))
+	for i, row := range rows {
+		result.Data[i] = row.PlayerSummary
+	}
+
+	if result.HasMore {
+		last := rows[len(rows)-1]
+		result.NextCursor = cursor.Encode(playerCursor{
+			Sort:       page.Sort,
+			Name:       last.NameKey,
+			Rating:     last.Rating,
+			LastActive: last.ActiveKey,
+			ID:         last.ID,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/ratelimit"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateLimitRepository — хранилище ограничителя частоты в Postgres, общее для всех
+// экземпляров сервиса. Каждая операция — транзакция с блокировкой строки ключа.
+type RateLimitRepository interface {
+	ratelimit.Store
+}
+
+type rateLimitRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewRateLimitRepository(db *gorm.DB, logger *slog.Logger) RateLimitRepository {
+	return &rateLimitRepository{db: db, logger: logger}
+}
+
+func (r *rateLimitRepository) Take(key string, limit ratelimit.Limit, now time.Time) (time.Duration, error) {
+	var retryAfter time.Duration
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Новая корзина создаётся полной; если её уже создал параллельный запрос, вставка пропускается
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(ratelimit.NewBucket(key, limit, now)).Error; err != nil {
+			return err
+		}
+
+		var bucket models.RateLimitBucket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&bucket).Error; err != nil {
+			return err
+		}
+
+		retryAfter = ratelimit.TakeToken(&bucket, limit, now)
+		return tx.Save(&bucket).Error
+	})
+	if err != nil {
+		r.logger.Error("ошибка обновления корзины лимита", "key", key, "error", err)
+		return 0, err
+	}
+
+	return retryAfter, nil
+}
+
+func (r *rateLimitRepository) Fail(key string, policy ratelimit.LockoutPolicy, now time.Time) (*time.Time, error) {
+	var lockedUntil *time.Time
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		empty := &models.RateLimitFailure{Key: key, ExpiresAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(empty).Error; err != nil {
+			return err
+		}
+
+		var failure models.RateLimitFailure
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&failure).Error; err != nil {
+			return err
+		}
+
+		ratelimit.RegisterFailure(&failure, policy, now)
+		lockedUntil = ratelimit.ActiveLock(&failure, now)
+		return tx.Save(&failure).Error
+	})
+	if err != nil {
+		r.logger.Error("ошибка учёта неудачной попытки", "key", key, "error", err)
+		return nil, err
+	}
+
+	return lockedUntil, nil
+}
+
+func (r *rateLimitRepository) LockedUntil(key string, now time.Time) (*time.Time, error) {
+	var failures []models.RateLimitFailure
+	if err := r.db.Where("key = ?", key).Limit(1).Find(&failures).Error; err != nil {
+		r.logger.Error("ошибка проверки блокировки", "key", key, "error", err)
+		return nil, err
+	}
+
+	if len(failures) == 0 {
+		return nil, nil
+	}
+	return ratelimit.ActiveLock(&failures[0], now), nil
+}
+
+func (r *rateLimitRepository) Reset(key string) error {
+	return r.db.Where("key = ?", key).Delete(&models.RateLimitFailure{}).Error
+}
+
+func (r *rateLimitRepository) Sweep(now time.Time) (int, error) {
+	buckets := r.db.Where("expires_at <= ?", now).Delete(&models.RateLimitBucket{})
+	if buckets.Error != nil {
+		return 0, buckets.Error
+	}
+
+	failures := r.db.Where("expires_at <= ?", now).Delete(&models.RateLimitFailure{})
+	if failures.Error != nil {
+		return int(buckets.RowsAffected), failures.Error
+	}
+
+	return int(buckets.RowsAffected + failures.RowsAffected), nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"log/slog"
+
+	"shumnaya/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RatingHistoryRepository interface {
+	WithDB(tx *gorm.DB) RatingHistoryRepository
+	CreateBatch(entries []models.RatingHistory) error
+
+	GetByMatchIDs(matchIDs []uint) ([]models.RatingHistory, error)
+	GetByPlayer(playerID uint, filter *models.RatingHistoryFilter) ([]models.RatingHistory, error)
+	DeleteByMatchIDs(matchIDs []uint) error
+	DeleteAll() error
+}
+
+type ratingHistoryRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewRatingHistoryRepository(db *gorm.DB, logger *slog.Logger) RatingHistoryRepository {
+	return &ratingHistoryRepository{db: db, logger: logger}
+}
+
+func (r *ratingHistoryRepository) WithDB(tx *gorm.DB) RatingHistoryRepository {
+	return &ratingHistoryRepository{db: tx, logger: r.logger}
+}
+
+func (r *ratingHistoryRepository) CreateBatch(entries []models.RatingHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	batchSize, err := insertBatchSize(r.db, &models.RatingHistory{}, 1000)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.CreateInBatches(&entries, batchSize).Error; err != nil {
+		r.logger.Error("ошибка записи истории рейтинга", "count", len(entries), "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *ratingHistoryRepository) GetByMatchIDs(matchIDs []uint) ([]models.RatingHistory, error) {
+	var entries []models.RatingHistory
+	if len(matchIDs) == 0 {
+		return entries, nil
+	}
+
+	for _, ids := range idChunks(matchIDs) {
+		var chunk []models.RatingHistory
+		if err := r.db.Where("match_id IN ?", ids).Find(&chunk).Error; err != nil {
+			r.logger.Error("ошибка получения истории рейтинга по матчам", "error", err)
+			return nil, err
+		}
+		entries = append(entries, chunk...)
+	}
+	return entries, nil
+}
+
+// GetByPlayer возвращает историю рейтинга игрока в хронологическом порядке.
+func (r *ratingHistoryRepository) GetByPlayer(playerID uint, filter *models.RatingHistoryFilter) ([]models.RatingHistory, error) {
+	var entries []models.RatingHistory
+
+	query := r.db.Where("player_id = ?", playerID)
+
+	if filter.SeasonID != nil {
+		query = query.Where("season_id = ?", *filter.SeasonID)
+	}
+	if filter.FromDate != nil {
+		query = query.Where("played_at >= ?", *filter.FromDate)
+	}
+	if filter.ToDate != nil {
+		query = query.Where("played_at <= ?", *filter.ToDate)
+	}
+
+	if err := query.Order("played_at ASC, match_id ASC").Find(&entries).Error; err != nil {
+		r.logger.Error("ошибка получения истории рейтинга игрока", "player_id", playerID, "error", err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// DeleteByMatchIDs удаляет записи физически: при пересчёте они заменяются новыми.
+func (r *ratingHistoryRepository) DeleteByMatchIDs(matchIDs []uint) error {
+	if len(matchIDs) == 0 {
+		return nil
+	}
+
+	for _, ids := range idChunks(matchIDs) {
+		if err := r.db.Unscoped().Where("match_id IN ?", ids).Delete(&models.RatingHistory{}).Error; err != nil {
+			r.logger.Error("ошибка удаления истории рейтинга", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ratingHistoryRepository) DeleteAll() error {
+	if err := r.db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.RatingHistory{}).Error; err != nil {
+		r.logger.Error("ошибка очистки истории рейтинга", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+
+	"shumnaya/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenRepository interface {
+	WithDB(tx *gorm.DB) RefreshTokenRepository
+	Create(token *models.RefreshToken) error
+
+	GetByHashForUpdate(hash string) (*models.RefreshToken, error)
+	MarkUsed(id uint, at time.Time) error
+	RevokeFamily(familyID string, at time.Time) error
+	RevokeByPlayer(playerID uint, at time.Time) error
+}
+
+type refreshTokenRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewRefreshTokenRepository(db *gorm.DB, logger *slog.Logger) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db, logger: logger}
+}
+
+func (r *refreshTokenRepository) WithDB(tx *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: tx, logger: r.logger}
+}
+
+func (r *refreshTokenRepository) Create(token *models.RefreshToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		r.logger.Error("ошибка сохранения refresh-токена", "player_id", token.PlayerID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetByHashForUpdate находит токен по хешу и блокирует строку до конца транзакции,
+// чтобы один токен нельзя было обменять дважды параллельными запросами.
+func (r *refreshTokenRepository) GetByHashForUpdate(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) MarkUsed(id uint, at time.Time) error {
+	return r.db.Model(&models.RefreshToken{}).Where("id = ?", id).Update("used_at", at).Error
+}
+
+// RevokeFamily отзывает все ещё не отозванные токены семьи.
+func (r *refreshTokenRepository) RevokeFamily(familyID string, at time.Time) error {
+	err := r.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+	if err != nil {
+		r.logger.Error("ошибка отзыва семьи refresh-токенов", "family_id", familyID, "error", err)
+	}
+	return err
+}
+
+// RevokeByPlayer отзывает все ещё не отозванные токены игрока (выход на всех устройствах).
+func (r *refreshTokenRepository) RevokeByPlayer(playerID uint, at time.Time) error {
+	err := r.db.Model(&models.RefreshToken{}).
+		Where("player_id = ? AND revoked_at IS NULL", playerID).
+		Update("revoked_at", at).Error
+	if err != nil {
+		r.logger.Error("ошибка отзыва refresh-токенов игрока", "player_id", playerID, "error", err)
+	}
+	return err
+}
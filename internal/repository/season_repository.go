@@ -1,21 +1,34 @@
 package repository
 
 import (
+	"errors"
 	"log/slog"
+	"time"
 
 	"shumnaya/internal/models"
+	"shumnaya/internal/utils/cursor"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SeasonRepository interface {
+	WithDB(tx *gorm.DB) SeasonRepository
 	Create(season *models.Season) error
 
 	GetByID(id uint) (*models.Season, error)
+	GetByIDForUpdate(id uint) (*models.Season, error)
+	GetByIDForShare(id uint) (*models.Season, error)
 	GetActive() (*models.Season, error)
 	GetAll() ([]models.Season, error)
+	GetPage(page models.PageRequest) (*models.SeasonPage, error)
+	GetEndedOpen(now time.Time) ([]models.Season, error)
+	GetStartable(now time.Time) (*models.Season, error)
+	GetLatest() (*models.Season, error)
+	ExistsOverlapping(start, end time.Time) (bool, error)
 
 	Update(season *models.Season) error
+	Deactivate(id uint) error
 	CloseSeason(id uint) error
 }
 
@@ -31,6 +44,10 @@ func NewSeasonRepository(db *gorm.DB, logger *slog.Logger) SeasonRepository {
 	}
 }
 
+func (r *seasonRepository) WithDB(tx *gorm.DB) SeasonRepository {
+	return &seasonRepository{db: tx, logger: r.logger}
+}
+
 func (r *seasonRepository) Create(season *models.Season) error {
 	if r.logger != nil {
 		r.logger.Info("создание сезона", "name", season.Name)
@@ -78,6 +95,34 @@ func (r *seasonRepository) GetByID(id uint) (*models.Season, error) {
 	return &season, nil
 }
 
+// GetByIDForUpdate блокирует строку сезона до конца транзакции (активация и закрытие).
+func (r *seasonRepository) GetByIDForUpdate(id uint) (*models.Season, error) {
+	var season models.Season
+
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&season, id).Error; err != nil {
+		if r.logger != nil {
+			r.logger.Error("ошибка при блокировке сезона", "season_id", id, "error", err)
+		}
+		return nil, err
+	}
+
+	return &season, nil
+}
+
+// GetByIDForShare не даёт закрыть сезон, пока транзакция записывает в него матчи.
+func (r *seasonRepository) GetByIDForShare(id uint) (*models.Season, error) {
+	var season models.Season
+
+	if err := r.db.Clauses(clause.Locking{Strength: "SHARE"}).First(&season, id).Error; err != nil {
+		if r.logger != nil {
+			r.logger.Error("ошибка при получении сезона", "season_id", id, "error", err)
+		}
+		return nil, err
+	}
+
+	return &season, nil
+}
+
 func (r *seasonRepository) GetActive() (*models.Season, error) {
 	var season models.Season
 
@@ -86,7 +131,7 @@ func (r *seasonRepository) GetActive() (*models.Season, error) {
 	}
 
 	if err := r.db.Where("is_active = ?", true).First(&season).Error; err != nil {
-		if r.logger != nil {
+		if r.logger != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			r.logger.Error("активный сезон не найден", "error", err)
 		}
 		return nil, err
@@ -95,6 +140,107 @@ func (r *seasonRepository) GetActive() (*models.Season, error) {
 	return &season, nil
 }
 
+// seasonCursor — ключ последнего сезона страницы.
+type seasonCursor struct {
+	Sort      string    `json:"s"`
+	StartDate time.Time `json:"d"`
+	ID        uint      `json:"i"`
+}
+
+// GetPage возвращает страницу сезонов с сортировкой по (start_date, id).
+func (r *seasonRepository) GetPage(page models.PageRequest) (*models.SeasonPage, error) {
+	result := &models.SeasonPage{PageInfo: models.PageInfo{Limit: page.Limit}}
+
+	if page.WithTotal {
+		var total int64
+		if err := r.db.Model(&models.Season{}).Count(&total).Error; err != nil {
+			if r.logger != nil {
+				r.logger.Error("ошибка подсчёта сезонов", "error", err)
+			}
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	dir, op := sortDirection(page.Sort)
+	query := r.db.Model(&models.Season{})
+
+	if page.Cursor != "" {
+		var after seasonCursor
+		if err := cursor.Decode(page.Cursor, &after); err != nil || after.Sort != page.Sort {
+			return nil, cursor.ErrInvalidCursor
+		}
+		query = query.Where("(start_date, id) "+op+" (?, ?)", after.StartDate, after.ID)
+	}
+
+	var seasons []models.Season
+	err := query.Order("start_date " + dir).Order("id " + dir).Limit(page.Limit + 1).Find(&seasons).Error
+	if err != nil {
+		if r.logger != nil {
+			r.logger.Error("ошибка при получении страницы сезонов", "error", err)
+		}
+		return nil, err
+	}
+
+	result.Data, result.HasMore = trimPage(seasons, page.Limit)
+	if result.HasMore {
+		last := result.Data[len(result.Data)-1]
+		result.NextCursor = cursor.Encode(seasonCursor{Sort: page.Sort, StartDate: last.StartDate, ID: last.ID})
+	}
+
+	return result, nil
+}
+
+// GetEndedOpen возвращает незакрытые сезоны, дата окончания которых уже прошла.
+func (r *seasonRepository) GetEndedOpen(now time.Time) ([]models.Season, error) {
+	var seasons []models.Season
+
+	if err := r.db.Where("closed_at IS NULL AND end_date < ?", now).Order("end_date ASC").Find(&seasons).Error; err != nil {
+		if r.logger != nil {
+			r.logger.Error("ошибка при получении закончившихся сезонов", "error", err)
+		}
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// GetStartable возвращает самый ранний неактивный незакрытый сезон, идущий в момент now.
+func (r *seasonRepository) GetStartable(now time.Time) (*models.Season, error) {
+	var season models.Season
+
+	err := r.db.
+		Where("closed_at IS NULL AND is_active = ? AND start_date <= ? AND end_date >= ?", false, now, now).
+		Order("start_date ASC, id ASC").
+		First(&season).Error
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// GetLatest возвращает сезон с самой поздней датой окончания.
+func (r *seasonRepository) GetLatest() (*models.Season, error) {
+	var season models.Season
+
+	if err := r.db.Order("end_date DESC, id DESC").First(&season).Error; err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// ExistsOverlapping проверяет, есть ли сезон, пересекающийся с периодом [start, end].
+func (r *seasonRepository) ExistsOverlapping(start, end time.Time) (bool, error) {
+	var count int64
+
+	if err := r.db.Model(&models.Season{}).Where("start_date <= ? AND end_date >= ?", end, start).Count(&count).Error; err != nil {
+		if r.logger != nil {
+			r.logger.Error("ошибка при проверке пересечения сезонов", "error", err)
+		}
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *seasonRepository) Update(season *models.Season) error {
 	if err := r.db.Save(season).Error; err != nil {
 		if r.logger != nil {
@@ -110,6 +256,17 @@ func (r *seasonRepository) Update(season *models.Season) error {
 	return nil
 }
 
+// Deactivate снимает с сезона отметку активного, не закрывая его.
+func (r *seasonRepository) Deactivate(id uint) error {
+	if err := r.db.Model(&models.Season{}).Where("id = ?", id).Update("is_active", false).Error; err != nil {
+		if r.logger != nil {
+			r.logger.Error("ошибка при снятии активности сезона", "season_id", id, "error", err)
+		}
+		return err
+	}
+	return nil
+}
+
 func (r *seasonRepository) CloseSeason(id uint) error {
 	var season models.Season
 
@@ -120,7 +277,9 @@ func (r *seasonRepository) CloseSeason(id uint) error {
 		return err
 	}
 
+	now := time.Now()
 	season.IsActive = false
+	season.ClosedAt = &now
 
 	if err := r.db.Save(&season).Error; err != nil {
 		if r.logger != nil {
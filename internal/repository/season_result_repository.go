@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"log/slog"
+
+	"shumnaya/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SeasonResultRepository хранит итоги закрытых сезонов. Записи только добавляются:
+// методов изменения и удаления нет намеренно.
+type SeasonResultRepository interface {
+	WithDB(tx *gorm.DB) SeasonResultRepository
+	CreateResults(results []models.SeasonResult) error
+	CreateAwards(awards []models.SeasonAward) error
+
+	GetResults(seasonID uint) ([]models.SeasonResult, error)
+	GetAwards(seasonID uint) ([]models.SeasonAward, error)
+}
+
+type seasonResultRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewSeasonResultRepository(db *gorm.DB, logger *slog.Logger) SeasonResultRepository {
+	return &seasonResultRepository{db: db, logger: logger}
+}
+
+func (r *seasonResultRepository) WithDB(tx *gorm.DB) SeasonResultRepository {
+	return &seasonResultRepository{db: tx, logger: r.logger}
+}
+
+func (r *seasonResultRepository) CreateResults(results []models.SeasonResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	if err := r.db.Omit(clause.Associations).CreateInBatches(&results, 1000).Error; err != nil {
+		r.logger.Error("ошибка сохранения итогов сезона", "season_id", results[0].SeasonID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *seasonResultRepository) CreateAwards(awards []models.SeasonAward) error {
+	if len(awards) == 0 {
+		return nil
+	}
+
+	if err := r.db.Omit(clause.Associations).Create(&awards).Error; err != nil {
+		r.logger.Error("ошибка сохранения наград сезона", "season_id", awards[0].SeasonID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *seasonResultRepository) GetResults(seasonID uint) ([]models.SeasonResult, error) {
+	var results []models.SeasonResult
+
+	err := r.db.Preload("Player").
+		Where("season_id = ?", seasonID).
+		Order("type ASC, rank ASC, player_id ASC").
+		Find(&results).Error
+	if err != nil {
+		r.logger.Error("ошибка получения итогов сезона", "season_id", seasonID, "error", err)
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *seasonResultRepository) GetAwards(seasonID uint) ([]models.SeasonAward, error) {
+	var awards []models.SeasonAward
+
+	err := r.db.Preload("Player").
+		Where("season_id = ?", seasonID).
+		Order("type ASC, id ASC").
+		Find(&awards).Error
+	if err != nil {
+		r.logger.Error("ошибка получения наград сезона", "season_id", seasonID, "error", err)
+		return nil, err
+	}
+	return awards, nil
+}
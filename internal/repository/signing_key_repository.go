@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+
+	"shumnaya/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// signingKeyRotationLock — ключ advisory-блокировки ротации: ключ выпускает только
+// один экземпляр сервиса, остальные ждут и перечитывают результат.
+const signingKeyRotationLock = 7305001
+
+type SigningKeyRepository interface {
+	WithDB(tx *gorm.DB) SigningKeyRepository
+	Create(key *models.SigningKey) error
+
+	LockRotation() error
+	GetUsable(now time.Time) ([]models.SigningKey, error)
+	Retire(kid string, retiredAt, expiresAt time.Time) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type signingKeyRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewSigningKeyRepository(db *gorm.DB, logger *slog.Logger) SigningKeyRepository {
+	return &signingKeyRepository{db: db, logger: logger}
+}
+
+func (r *signingKeyRepository) WithDB(tx *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{db: tx, logger: r.logger}
+}
+
+func (r *signingKeyRepository) Create(key *models.SigningKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		r.logger.Error("ошибка сохранения ключа подписи", "kid", key.KID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// LockRotation берёт advisory-блокировку до конца транзакции.
+func (r *signingKeyRepository) LockRotation() error {
+	return r.db.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyRotationLock).Error
+}
+
+// GetUsable возвращает ключи, которые ещё проверяют токены, от новых к старым.
+func (r *signingKeyRepository) GetUsable(now time.Time) ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+
+	err := r.db.
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		r.logger.Error("ошибка получения ключей подписи", "error", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Retire выводит ключ из оборота: он перестаёт подписывать и проверяет токены до expiresAt.
+func (r *signingKeyRepository) Retire(kid string, retiredAt, expiresAt time.Time) error {
+	result := r.db.Model(&models.SigningKey{}).
+		Where("kid = ? AND retired_at IS NULL", kid).
+		Updates(map[string]interface{}{"retired_at": retiredAt, "expires_at": expiresAt})
+	if result.Error != nil {
+		r.logger.Error("ошибка вывода ключа подписи", "kid", kid, "error", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (r *signingKeyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.SigningKey{})
+	if result.Error != nil {
+		r.logger.Error("ошибка удаления истёкших ключей подписи", "error", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"shumnaya/internal/models"
+)
+
+// tieBreakColumns — выражения критериев над строкой metrics (алиас g) и h2h (алиас h).
+var tieBreakColumns = map[string]string{
+	models.TieBreakPoints:      "g.points",
+	models.TieBreakWins:        "g.wins",
+	models.TieBreakWinDiff:     "g.win_diff",
+	models.TieBreakHeadToHead:  "COALESCE(h.h2h, 0)",
+	models.TieBreakGamesRatio:  "g.games_ratio",
+	models.TieBreakPointsRatio: "g.points_ratio",
+	models.TieBreakBuchholz:    "g.buchholz",
+	models.TieBreakRating:      "g.rating",
+}
+
+// rankMetricsSQL считает показатели игроков таблицы по подтверждённым матчам сезона:
+// part — по строке на участника матча, agg — партии и розыгрыши, buch — коэффициент
+// Бухгольца (для пар — средние очки пары соперников за матч).
+const rankMetricsSQL = `
+WITH st AS (
+	SELECT id, player_id, wins, losses, points, rating
+	FROM standings
+	WHERE season_id = @season AND type = @type AND deleted_at IS NULL
+),
+part AS (
+	SELECT m.id AS match_id, v.player_id, v.won,
+		CASE WHEN v.won THEN m.winner_games ELSE m.loser_games END AS games_for,
+		CASE WHEN v.won THEN m.loser_games ELSE m.winner_games END AS games_against,
+		CASE WHEN v.won THEN g.winner_points ELSE g.loser_points END AS rally_for,
+		CASE WHEN v.won THEN g.loser_points ELSE g.winner_points END AS rally_against
+	FROM matches m
+	CROSS JOIN LATERAL (VALUES
+		(m.winner_id, true), (m.winner_partner_id, true), (m.loser_id, false), (m.loser_partner_id, false)
+	) AS v(player_id, won)
+	CROSS JOIN LATERAL (
+		SELECT COALESCE(SUM(mg.winner_points), 0) AS winner_points, COALESCE(SUM(mg.loser_points), 0) AS loser_points
+		FROM match_games mg
+		WHERE mg.match_id = m.id AND mg.deleted_at IS NULL
+	) AS g
+	WHERE m.season_id = @season AND m.type = @type AND m.status = @confirmed
+		AND m.deleted_at IS NULL AND v.player_id IS NOT NULL
+),
+agg AS (
+	SELECT player_id,
+		SUM(games_for) AS games_for, SUM(games_against) AS games_against,
+		SUM(rally_for) AS rally_for, SUM(rally_against) AS rally_against
+	FROM part
+	GROUP BY player_id
+),
+opp AS (
+	SELECT p.match_id, p.won, AVG(COALESCE(s.points, 0)) AS points
+	FROM part p
+	LEFT JOIN st s ON s.player_id = p.player_id
+	GROUP BY p.match_id, p.won
+),
+buch AS (
+	SELECT p.player_id, SUM(o.points) AS buchholz
+	FROM part p
+	JOIN opp o ON o.match_id = p.match_id AND o.won <> p.won
+	GROUP BY p.player_id
+),
+metrics AS (
+	SELECT st.id, st.player_id, st.wins, st.points, st.rating,
+		st.wins - st.losses AS win_diff,
+		COALESCE(a.games_for::float / NULLIF(a.games_for + a.games_against, 0), 0) AS games_ratio,
+		COALESCE(a.rally_for::float / NULLIF(a.rally_for + a.rally_against, 0), 0) AS points_ratio,
+		COALESCE(b.buchholz, 0) AS buchholz
+	FROM st
+	LEFT JOIN agg a ON a.player_id = st.player_id
+	LEFT JOIN buch b ON b.player_id = st.player_id
+)`
+
+// headToHeadSQL делит игроков на группы, равные по критериям до head_to_head, и считает
+// победы каждого над соперниками из своей группы.
+const headToHeadSQL = `,
+grouped AS (
+	SELECT g.*, DENSE_RANK() OVER (%s) AS grp
+	FROM metrics g
+),
+h2h AS (
+	SELECT p.player_id, COUNT(DISTINCT p.match_id) AS h2h
+	FROM part p
+	JOIN grouped gp ON gp.player_id = p.player_id
+	JOIN part o ON o.match_id = p.match_id AND NOT o.won
+	JOIN grouped gq ON gq.player_id = o.player_id AND gq.grp = gp.grp
+	WHERE p.won
+	GROUP BY p.player_id
+)`
+
+// rankUpdateSQL проставляет места (равные по всем критериям делят место: 1, 2, 2, 4)
+// и записывает изменившиеся места в недельный снимок.
+const rankUpdateSQL = `,
+ranked AS (
+	SELECT g.id, RANK() OVER (ORDER BY %s) AS rank
+	FROM %s
+),
+updated AS (
+	UPDATE standings s SET rank = r.rank, updated_at = NOW()
+	FROM ranked r
+	WHERE s.id = r.id AND s.rank IS DISTINCT FROM r.rank
+	RETURNING s.season_id, s.type, s.player_id, s.rank
+)
+INSERT INTO standing_rank_snapshots (created_at, updated_at, season_id, type, player_id, week_start, rank)
+SELECT NOW(), NOW(), season_id, type, player_id, @week::date, rank FROM updated
+ON CONFLICT (season_id, type, player_id, week_start)
+DO UPDATE SET rank = EXCLUDED.rank, updated_at = EXCLUDED.updated_at`
+
+// RecalculateRanks пересчитывает места в таблице сезона по критериям tieBreakers
+// одним запросом и сохраняет изменившиеся места в снимок недели weekStart.
+func (r *standingRepository) RecalculateRanks(seasonID uint, matchType string, tieBreakers []string, weekStart time.Time) error {
+	order := make([]string, 0, len(tieBreakers))
+	h2hAt := -1
+	for i, c := range tieBreakers {
+		col, ok := tieBreakColumns[c]
+		if !ok {
+			return fmt.Errorf("unknown tie breaker %q", c)
+		}
+		if c == models.TieBreakHeadToHead {
+			h2hAt = i
+		}
+		order = append(order, col+" DESC")
+	}
+
+	query := rankMetricsSQL
+	from := "metrics g"
+	if h2hAt >= 0 {
+		window := ""
+		if h2hAt > 0 {
+			window = "ORDER BY " + strings.Join(order[:h2hAt], ", ")
+		}
+		query += fmt.Sprintf(headToHeadSQL, window)
+		from = "grouped g LEFT JOIN h2h h ON h.player_id = g.player_id"
+	}
+	query += fmt.Sprintf(rankUpdateSQL, strings.Join(order, ", "), from)
+
+	err := r.db.Exec(query, map[string]interface{}{
+		"season":    seasonID,
+		"type":      matchType,
+		"confirmed": models.MatchStatusConfirmed,
+		"week":      weekStart.Format(time.DateOnly), // дата без часового пояса: неделя считается по локальному времени сервера
+	}).Error
+	if err != nil {
+		r.logger.Error("ошибка пересчёта мест в таблице", "season_id", seasonID, "type", matchType, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetPreviousRanks возвращает места игроков по последнему снимку до недели weekStart.
+func (r *standingRepository) GetPreviousRanks(seasonID uint, matchType string, weekStart time.Time) (map[uint]int, error) {
+	var snapshots []models.StandingRankSnapshot
+
+	err := r.db.Raw(`
+		SELECT DISTINCT ON (player_id) player_id, rank
+		FROM standing_rank_snapshots
+		WHERE season_id = ? AND type = ? AND week_start < ?::date AND deleted_at IS NULL
+		ORDER BY player_id, week_start DESC`, seasonID, matchType, weekStart.Format(time.DateOnly)).
+		Scan(&snapshots).Error
+	if err != nil {
+		r.logger.Error("ошибка получения мест прошлой недели", "season_id", seasonID, "type", matchType, "error", err)
+		return nil, err
+	}
+
+	ranks := make(map[uint]int, len(snapshots))
+	for _, s := range snapshots {
+		ranks[s.PlayerID] = s.Rank
+	}
+	return ranks, nil
+}
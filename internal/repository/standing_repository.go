@@ -2,11 +2,13 @@ package repository
 
 import (
 	"log/slog"
-	"sort"
+	"time"
 
 	"shumnaya/internal/models"
+	"shumnaya/internal/utils/cursor"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StandingRepository interface {
@@ -15,11 +17,15 @@ type StandingRepository interface {
 	Update(standing *models.Standing) error
 
 	CreateOrUpdate(standing *models.Standing) error
+	ReplaceAll(standings []*models.Standing, batchSize int) error
+	RecalculateRanks(seasonID uint, matchType string, tieBreakers []string, weekStart time.Time) error
+	GetPreviousRanks(seasonID uint, matchType string, weekStart time.Time) (map[uint]int, error)
 
-	GetByPlayerAndSeason(playerID, seasonID uint) (*models.Standing, error)
+	GetByPlayerAndSeason(playerID, seasonID uint, matchType string) (*models.Standing, error)
 	GetBySeason(seasonID uint) ([]models.Standing, error)
 
-	GetSeasonStandingsOrdered(seasonID uint) ([]models.Standing, error)
+	GetSeasonStandingsOrdered(seasonID uint, matchType, orderBy string) ([]models.Standing, error)
+	GetSeasonStandingsPage(seasonID uint, matchType, orderBy string, page models.PageRequest) (*models.StandingPage, error)
 }
 
 type standingRepository struct {
@@ -45,7 +51,7 @@ func (r *standingRepository) Update(standing *models.Standing) error {
 
 func (r *standingRepository) CreateOrUpdate(standing *models.Standing) error {
 	var existing models.Standing
-	err := r.db.Where("player_id = ? AND season_id = ?", standing.PlayerID, standing.SeasonID).First(&existing).Error
+	err := r.db.Where("player_id = ? AND season_id = ? AND type = ?", standing.PlayerID, standing.SeasonID, standing.Type).First(&existing).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return r.Create(standing)
@@ -56,14 +62,39 @@ func (r *standingRepository) CreateOrUpdate(standing *models.Standing) error {
 	existing.Wins = standing.Wins
 	existing.Losses = standing.Losses
 	existing.Points = standing.Points
-	existing.Rank = standing.Rank
+	existing.Rating = standing.Rating
+	existing.RatingDeviation = standing.RatingDeviation
+	existing.RatingVolatility = standing.RatingVolatility
 
 	return r.Update(&existing)
 }
 
-func (r *standingRepository) GetByPlayerAndSeason(playerID, seasonID uint) (*models.Standing, error) {
+// ReplaceAll заменяет все строки турнирных таблиц переданными (используется при полном пересчёте).
+func (r *standingRepository) ReplaceAll(standings []*models.Standing, batchSize int) error {
+	if err := r.db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.Standing{}).Error; err != nil {
+		r.logger.Error("ошибка очистки турнирных таблиц", "error", err)
+		return err
+	}
+
+	if len(standings) == 0 {
+		return nil
+	}
+
+	batchSize, err := insertBatchSize(r.db, &models.Standing{}, batchSize)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.Omit(clause.Associations).CreateInBatches(standings, batchSize).Error; err != nil {
+		r.logger.Error("ошибка записи турнирных таблиц", "count", len(standings), "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *standingRepository) GetByPlayerAndSeason(playerID, seasonID uint, matchType string) (*models.Standing, error) {
 	var s models.Standing
-	if err := r.db.Preload("Player").Preload("Season").Where("player_id = ? AND season_id = ?", playerID, seasonID).First(&s).Error; err != nil {
+	if err := r.db.Preload("Player").Preload("Season").Where("player_id = ? AND season_id = ? AND type = ?", playerID, seasonID, matchType).First(&s).Error; err != nil {
 		return nil, err
 	}
 	return &s, nil
@@ -77,36 +108,95 @@ func (r *standingRepository) GetBySeason(seasonID uint) ([]models.Standing, erro
 	return standings, nil
 }
 
-func (r *standingRepository) GetSeasonStandingsOrdered(seasonID uint) ([]models.Standing, error) {
+// GetSeasonStandingsOrdered возвращает таблицу сезона по сохранённым местам
+// (строки без места — в конце) либо по сезонному рейтингу.
+func (r *standingRepository) GetSeasonStandingsOrdered(seasonID uint, matchType, orderBy string) ([]models.Standing, error) {
 
 	var standings []models.Standing
 
 	err := r.db.
 		Preload("Player").
-		Where("season_id = ?", seasonID).
+		Where("season_id = ? AND type = ?", seasonID, matchType).
+		Order(standingOrderKey(orderBy)).
 		Find(&standings).Error
-
 	if err != nil {
 		r.logger.Error("ошибка при получении standings", "season_id", seasonID, "error", err)
 		return nil, err
 	}
 
-	sort.Slice(standings, func(i, j int) bool {
+	r.logger.Info("standings получены", "season_id", seasonID, "type", matchType, "order_by", orderBy, "count", len(standings))
+
+	return standings, nil
+}
+
+// standingCursor — ключ последней строки страницы таблицы.
+type standingCursor struct {
+	Sort     string `json:"s"`
+	Rank     int    `json:"r"`
+	Rating   int    `json:"t"`
+	PlayerID uint   `json:"i"`
+}
+
+// standingOrderKey — ключ сортировки таблицы, все части по возрастанию, чтобы по нему
+// можно было листать сравнением кортежей: строки без места идут в конце, рейтинг — по убыванию.
+func standingOrderKey(orderBy string) string {
+	if orderBy == models.StandingsOrderRating {
+		return "-rating, CASE WHEN rank = 0 THEN 1 ELSE 0 END, rank, player_id"
+	}
+	return "CASE WHEN rank = 0 THEN 1 ELSE 0 END, rank, -rating, player_id"
+}
+
+// standingOrderValues — значения ключа standingOrderKey для строки из курсора.
+func standingOrderValues(orderBy string, c standingCursor) []interface{} {
+	unranked := 0
+	if c.Rank == 0 {
+		unranked = 1
+	}
+	if orderBy == models.StandingsOrderRating {
+		return []interface{}{-c.Rating, unranked, c.Rank, c.PlayerID}
+	}
+	return []interface{}{unranked, c.Rank, -c.Rating, c.PlayerID}
+}
 
-		if standings[i].Points != standings[j].Points {
-			return standings[i].Points > standings[j].Points
+// GetSeasonStandingsPage возвращает страницу таблицы сезона в том же порядке,
+// что и GetSeasonStandingsOrdered.
+func (r *standingRepository) GetSeasonStandingsPage(seasonID uint, matchType, orderBy string, page models.PageRequest) (*models.StandingPage, error) {
+	result := &models.StandingPage{PageInfo: models.PageInfo{Limit: page.Limit}}
+
+	if page.WithTotal {
+		var total int64
+		err := r.db.Model(&models.Standing{}).Where("season_id = ? AND type = ?", seasonID, matchType).Count(&total).Error
+		if err != nil {
+			r.logger.Error("ошибка подсчёта standings", "season_id", seasonID, "error", err)
+			return nil, err
 		}
+		result.Total = &total
+	}
 
-		diffI := standings[i].Wins - standings[i].Losses
-		diffJ := standings[j].Wins - standings[j].Losses
-		if diffI != diffJ {
-			return diffI > diffJ
+	query := r.db.
+		Preload("Player").
+		Where("season_id = ? AND type = ?", seasonID, matchType)
+
+	if page.Cursor != "" {
+		var after standingCursor
+		if err := cursor.Decode(page.Cursor, &after); err != nil || after.Sort != orderBy {
+			return nil, cursor.ErrInvalidCursor
 		}
+		query = query.Where("("+standingOrderKey(orderBy)+") > (?, ?, ?, ?)", standingOrderValues(orderBy, after)...)
+	}
 
-		return standings[i].Player.Rating > standings[j].Player.Rating
-	})
+	var standings []models.Standing
+	err := query.Order(standingOrderKey(orderBy)).Limit(page.Limit + 1).Find(&standings).Error
+	if err != nil {
+		r.logger.Error("ошибка при получении страницы standings", "season_id", seasonID, "error", err)
+		return nil, err
+	}
 
-	r.logger.Info("standings отсортированы", "season_id", seasonID, "count", len(standings))
+	result.Data, result.HasMore = trimPage(standings, page.Limit)
+	if result.HasMore {
+		last := result.Data[len(result.Data)-1]
+		result.NextCursor = cursor.Encode(standingCursor{Sort: orderBy, Rank: last.Rank, Rating: last.Rating, PlayerID: last.PlayerID})
+	}
 
-	return standings, nil
+	return result, nil
 }
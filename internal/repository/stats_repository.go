@@ -0,0 +1,461 @@
+package repository
+
+import (
+	"log/slog"
+	"strings"
+
+	"shumnaya/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StatsRepository считает статистику игрока агрегатами в SQL, не загружая его матчи.
+type StatsRepository interface {
+	WithDB(tx *gorm.DB) StatsRepository
+
+	GetSummary(playerID uint, matchType string) (*models.PlayerStats, error)
+	GetStreaks(playerID uint, matchType string) (*models.PlayerStreaks, error)
+	GetRecentResults(playerID uint, matchType string, limit int) ([]bool, error)
+	GetSeasonSplits(playerID uint, matchType string) ([]models.SeasonSplit, error)
+	GetOpponents(playerID uint, matchType string, nemeses bool, limit int) ([]models.OpponentRecord, error)
+
+	GetHeadToHead(playerAID, playerBID uint, matchType string) (*models.HeadToHeadRecord, error)
+	GetHeadToHeadStreaks(playerAID, playerBID uint, matchType string) (*models.HeadToHeadStreaks, error)
+	GetHeadToHeadSeasons(playerAID, playerBID uint, matchType string) ([]models.HeadToHeadSeason, error)
+
+	GetCalibration(filter models.CalibrationFilter, buckets int) ([]models.CalibrationBucketStats, error)
+}
+
+type statsRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewStatsRepository(db *gorm.DB, logger *slog.Logger) StatsRepository {
+	return &statsRepository{db: db, logger: logger}
+}
+
+func (r *statsRepository) WithDB(tx *gorm.DB) StatsRepository {
+	return &statsRepository{db: tx, logger: r.logger}
+}
+
+// playerMatchesCTE — подтверждённые матчи игрока @player с его стороны: won, партии,
+// рейтинги своей стороны и соперников перед матчем и id соперников. Пустой @type — любой разряд.
+const playerMatchesCTE = `
+WITH pm AS (
+	SELECT m.id, m.played_at, m.season_id, m.forfeit, x.won,
+		CASE WHEN x.won THEN m.winner_games ELSE m.loser_games END AS games_won,
+		CASE WHEN x.won THEN m.loser_games ELSE m.winner_games END AS games_lost,
+		CASE WHEN x.won THEN m.winner_rating_before ELSE m.loser_rating_before END AS own_rating,
+		CASE WHEN x.won THEN m.loser_rating_before ELSE m.winner_rating_before END AS opponent_rating,
+		CASE WHEN x.won THEN ARRAY[m.loser_id, m.loser_partner_id] ELSE ARRAY[m.winner_id, m.winner_partner_id] END AS opponent_ids
+	FROM matches m
+	CROSS JOIN LATERAL (
+		SELECT m.winner_id = @player OR COALESCE(m.winner_partner_id = @player, false) AS won
+	) x
+	WHERE m.deleted_at IS NULL
+		AND m.status = @confirmed
+		AND (m.winner_id = @player OR m.loser_id = @player OR m.winner_partner_id = @player OR m.loser_partner_id = @player)
+		AND (@type = '' OR COALESCE(NULLIF(m.type, ''), @singles) = @type)
+)`
+
+func statsArgs(playerID uint, matchType string) map[string]interface{} {
+	return map[string]interface{}{
+		"player":    playerID,
+		"type":      matchType,
+		"confirmed": models.MatchStatusConfirmed,
+		"singles":   models.MatchTypeSingles,
+	}
+}
+
+// GetSummary считает общий счёт, партии и очки по партиям, а также результаты против
+// соперников сильнее и слабее по рейтингу. Производные доли не заполняются.
+func (r *statsRepository) GetSummary(playerID uint, matchType string) (*models.PlayerStats, error) {
+	var row struct {
+		Matches       int
+		Wins          int
+		GamesWon      int
+		GamesLost     int
+		PointsWon     int
+		PointsLost    int
+		HigherMatches int
+		HigherWins    int
+		LowerMatches  int
+		LowerWins     int
+	}
+
+	query := playerMatchesCTE + `
+	SELECT COUNT(*) AS matches,
+		COUNT(*) FILTER (WHERE pm.won) AS wins,
+		COALESCE(SUM(pm.games_won), 0) AS games_won,
+		COALESCE(SUM(pm.games_lost), 0) AS games_lost,
+		COALESCE(SUM(g.points_won), 0) AS points_won,
+		COALESCE(SUM(g.points_lost), 0) AS points_lost,
+		COUNT(*) FILTER (WHERE NOT pm.forfeit AND pm.opponent_rating > pm.own_rating) AS higher_matches,
+		COUNT(*) FILTER (WHERE NOT pm.forfeit AND pm.opponent_rating > pm.own_rating AND pm.won) AS higher_wins,
+		COUNT(*) FILTER (WHERE NOT pm.forfeit AND pm.opponent_rating < pm.own_rating) AS lower_matches,
+		COUNT(*) FILTER (WHERE NOT pm.forfeit AND pm.opponent_rating < pm.own_rating AND pm.won) AS lower_wins
+	FROM pm
+	LEFT JOIN LATERAL (
+		SELECT SUM(CASE WHEN pm.won THEN mg.winner_points ELSE mg.loser_points END) AS points_won,
+			SUM(CASE WHEN pm.won THEN mg.loser_points ELSE mg.winner_points END) AS points_lost
+		FROM match_games mg
+		WHERE mg.match_id = pm.id AND mg.deleted_at IS NULL
+	) g ON true`
+
+	if err := r.db.Raw(query, statsArgs(playerID, matchType)).Scan(&row).Error; err != nil {
+		r.logger.Error("ошибка подсчёта статистики игрока", "player_id", playerID, "error", err)
+		return nil, err
+	}
+
+	return &models.PlayerStats{
+		PlayerID: playerID,
+		Type:     matchType,
+		Summary: models.PlayerStatsSummary{
+			Matches:    row.Matches,
+			Wins:       row.Wins,
+			Losses:     row.Matches - row.Wins,
+			GamesWon:   row.GamesWon,
+			GamesLost:  row.GamesLost,
+			PointsWon:  row.PointsWon,
+			PointsLost: row.PointsLost,
+		},
+		VsHigherRated: models.RecordSplit{Matches: row.HigherMatches, Wins: row.HigherWins, Losses: row.HigherMatches - row.HigherWins},
+		VsLowerRated:  models.RecordSplit{Matches: row.LowerMatches, Wins: row.LowerWins, Losses: row.LowerMatches - row.LowerWins},
+	}, nil
+}
+
+// GetStreaks находит серии подряд идущих побед и поражений (gaps and islands по
+// порядку матчей) и серию, в которой находится последний матч.
+func (r *statsRepository) GetStreaks(playerID uint, matchType string) (*models.PlayerStreaks, error) {
+	var streaks models.PlayerStreaks
+
+	query := playerMatchesCTE + `,
+	ordered AS (
+		SELECT pm.won,
+			ROW_NUMBER() OVER (ORDER BY pm.played_at, pm.id) AS rn,
+			ROW_NUMBER() OVER (ORDER BY pm.played_at, pm.id)
+				- ROW_NUMBER() OVER (PARTITION BY pm.won ORDER BY pm.played_at, pm.id) AS grp
+		FROM pm
+	),
+	runs AS (
+		SELECT won, COUNT(*) AS length, MAX(rn) AS last_rn
+		FROM ordered
+		GROUP BY won, grp
+	)
+	SELECT COALESCE(MAX(length) FILTER (WHERE won), 0) AS longest_win,
+		COALESCE(MAX(length) FILTER (WHERE NOT won), 0) AS longest_loss,
+		COALESCE((SELECT CASE WHEN won THEN length ELSE -length END FROM runs ORDER BY last_rn DESC LIMIT 1), 0) AS current
+	FROM runs`
+
+	if err := r.db.Raw(query, statsArgs(playerID, matchType)).Scan(&streaks).Error; err != nil {
+		r.logger.Error("ошибка подсчёта серий игрока", "player_id", playerID, "error", err)
+		return nil, err
+	}
+	return &streaks, nil
+}
+
+// GetRecentResults возвращает исходы последних limit матчей игрока, новые первыми.
+func (r *statsRepository) GetRecentResults(playerID uint, matchType string, limit int) ([]bool, error) {
+	var rows []struct{ Won bool }
+
+	args := statsArgs(playerID, matchType)
+	args["limit"] = limit
+
+	query := playerMatchesCTE + `
+	SELECT pm.won FROM pm ORDER BY pm.played_at DESC, pm.id DESC LIMIT @limit`
+
+	if err := r.db.Raw(query, args).Scan(&rows).Error; err != nil {
+		r.logger.Error("ошибка получения формы игрока", "player_id", playerID, "error", err)
+		return nil, err
+	}
+
+	results := make([]bool, len(rows))
+	for i, row := range rows {
+		results[i] = row.Won
+	}
+	return results, nil
+}
+
+// GetSeasonSplits считает результаты игрока по сезонам, новые сезоны первыми.
+func (r *statsRepository) GetSeasonSplits(playerID uint, matchType string) ([]models.SeasonSplit, error) {
+	var rows []struct {
+		SeasonID   uint
+		SeasonName string
+		Matches    int
+		Wins       int
+		GamesWon   int
+		GamesLost  int
+	}
+
+	query := playerMatchesCTE + `
+	SELECT pm.season_id, s.name AS season_name,
+		COUNT(*) AS matches,
+		COUNT(*) FILTER (WHERE pm.won) AS wins,
+		SUM(pm.games_won) AS games_won,
+		SUM(pm.games_lost) AS games_lost
+	FROM pm
+	JOIN seasons s ON s.id = pm.season_id
+	GROUP BY pm.season_id, s.name, s.start_date
+	ORDER BY s.start_date DESC, pm.season_id DESC`
+
+	if err := r.db.Raw(query, statsArgs(playerID, matchType)).Scan(&rows).Error; err != nil {
+		r.logger.Error("ошибка подсчёта статистики игрока по сезонам", "player_id", playerID, "error", err)
+		return nil, err
+	}
+
+	splits := make([]models.SeasonSplit, len(rows))
+	for i, row := range rows {
+		splits[i] = models.SeasonSplit{
+			SeasonID:    row.SeasonID,
+			SeasonName:  row.SeasonName,
+			RecordSplit: models.RecordSplit{Matches: row.Matches, Wins: row.Wins, Losses: row.Matches - row.Wins},
+			GamesWon:    row.GamesWon,
+			GamesLost:   row.GamesLost,
+		}
+	}
+	return splits, nil
+}
+
+// GetOpponents возвращает соперников (включая игроков пар), которых игрок чаще всего
+// обыгрывает, либо при nemeses — которым он чаще всего проигрывает.
+func (r *statsRepository) GetOpponents(playerID uint, matchType string, nemeses bool, limit int) ([]models.OpponentRecord, error) {
+	var rows []struct {
+		OpponentID uint
+		Name       string
+		Matches    int
+		Wins       int
+	}
+
+	args := statsArgs(playerID, matchType)
+	args["limit"] = limit
+
+	order := "opp.wins DESC"
+	having := "opp.wins > 0"
+	if nemeses {
+		order = "(opp.matches - opp.wins) DESC"
+		having = "opp.matches > opp.wins"
+	}
+
+	query := playerMatchesCTE + `,
+	opp AS (
+		SELECT o.opponent_id, COUNT(*) AS matches, COUNT(*) FILTER (WHERE pm.won) AS wins
+		FROM pm
+		CROSS JOIN LATERAL unnest(pm.opponent_ids) AS o(opponent_id)
+		WHERE o.opponent_id IS NOT NULL
+		GROUP BY o.opponent_id
+	)
+	SELECT opp.opponent_id, p.name, opp.matches, opp.wins
+	FROM opp
+	JOIN players p ON p.id = opp.opponent_id
+	WHERE ` + having + `
+	ORDER BY ` + order + `, opp.matches ASC, opp.opponent_id ASC
+	LIMIT @limit`
+
+	if err := r.db.Raw(query, args).Scan(&rows).Error; err != nil {
+		r.logger.Error("ошибка подсчёта соперников игрока", "player_id", playerID, "error", err)
+		return nil, err
+	}
+
+	records := make([]models.OpponentRecord, len(rows))
+	for i, row := range rows {
+		records[i] = models.OpponentRecord{
+			OpponentID:  row.OpponentID,
+			Name:        row.Name,
+			RecordSplit: models.RecordSplit{Matches: row.Matches, Wins: row.Wins, Losses: row.Matches - row.Wins},
+		}
+	}
+	return records, nil
+}
+
+// headToHeadCTE — встречи игроков @a и @b с точки зрения игрока A: исход, партии
+// и изменения рейтинга каждого из двух игроков.
+const headToHeadCTE = `
+WITH h AS (
+	SELECT id, played_at, season_id,
+		COALESCE(@a IN (winner_id, winner_partner_id), false) AS a_won,
+		winner_games, loser_games,
+		CASE WHEN winner_id = @a THEN winner_rating_change
+			WHEN winner_partner_id = @a THEN winner_partner_rating_change
+			WHEN loser_id = @a THEN loser_rating_change
+			ELSE loser_partner_rating_change END AS a_change,
+		CASE WHEN winner_id = @b THEN winner_rating_change
+			WHEN winner_partner_id = @b THEN winner_partner_rating_change
+			WHEN loser_id = @b THEN loser_rating_change
+			ELSE loser_partner_rating_change END AS b_change
+	FROM matches
+	WHERE ` + headToHeadCondition + `
+)`
+
+// GetHeadToHead одним запросом считает счёт встреч, партии, очки и изменения рейтинга.
+func (r *statsRepository) GetHeadToHead(playerAID, playerBID uint, matchType string) (*models.HeadToHeadRecord, error) {
+	var row struct {
+		TotalMatches        int
+		PlayerAWins         int
+		PlayerBWins         int
+		PlayerAGames        int
+		PlayerBGames        int
+		PlayerAPoints       int
+		PlayerBPoints       int
+		PlayerARatingChange int
+		PlayerBRatingChange int
+	}
+
+	query := headToHeadCTE + `
+	SELECT COUNT(*) AS total_matches,
+		COUNT(*) FILTER (WHERE h.a_won) AS player_a_wins,
+		COUNT(*) FILTER (WHERE NOT h.a_won) AS player_b_wins,
+		COALESCE(SUM(CASE WHEN h.a_won THEN h.winner_games ELSE h.loser_games END), 0) AS player_a_games,
+		COALESCE(SUM(CASE WHEN h.a_won THEN h.loser_games ELSE h.winner_games END), 0) AS player_b_games,
+		COALESCE(SUM(g.a_points), 0) AS player_a_points,
+		COALESCE(SUM(g.b_points), 0) AS player_b_points,
+		COALESCE(SUM(h.a_change), 0) AS player_a_rating_change,
+		COALESCE(SUM(h.b_change), 0) AS player_b_rating_change
+	FROM h
+	LEFT JOIN LATERAL (
+		SELECT SUM(CASE WHEN h.a_won THEN mg.winner_points ELSE mg.loser_points END) AS a_points,
+			SUM(CASE WHEN h.a_won THEN mg.loser_points ELSE mg.winner_points END) AS b_points
+		FROM match_games mg
+		WHERE mg.match_id = h.id AND mg.deleted_at IS NULL
+	) g ON true`
+
+	if err := r.db.Raw(query, headToHeadArgs(playerAID, playerBID, matchType)).Scan(&row).Error; err != nil {
+		r.logger.Error("ошибка подсчёта встреч игроков", "player_a_id", playerAID, "player_b_id", playerBID, "error", err)
+		return nil, err
+	}
+
+	return &models.HeadToHeadRecord{
+		PlayerAID:           playerAID,
+		PlayerBID:           playerBID,
+		Type:                matchType,
+		TotalMatches:        row.TotalMatches,
+		PlayerAWins:         row.PlayerAWins,
+		PlayerBWins:         row.PlayerBWins,
+		PlayerAGames:        row.PlayerAGames,
+		PlayerBGames:        row.PlayerBGames,
+		PlayerAPoints:       row.PlayerAPoints,
+		PlayerBPoints:       row.PlayerBPoints,
+		PlayerARatingChange: row.PlayerARatingChange,
+		PlayerBRatingChange: row.PlayerBRatingChange,
+	}, nil
+}
+
+// GetHeadToHeadStreaks находит серии побед каждого игрока внутри противостояния.
+func (r *statsRepository) GetHeadToHeadStreaks(playerAID, playerBID uint, matchType string) (*models.HeadToHeadStreaks, error) {
+	var streaks models.HeadToHeadStreaks
+
+	query := headToHeadCTE + `,
+	ordered AS (
+		SELECT h.a_won,
+			ROW_NUMBER() OVER (ORDER BY h.played_at, h.id) AS rn,
+			ROW_NUMBER() OVER (ORDER BY h.played_at, h.id)
+				- ROW_NUMBER() OVER (PARTITION BY h.a_won ORDER BY h.played_at, h.id) AS grp
+		FROM h
+	),
+	runs AS (
+		SELECT a_won, COUNT(*) AS length, MAX(rn) AS last_rn
+		FROM ordered
+		GROUP BY a_won, grp
+	)
+	SELECT COALESCE(MAX(length) FILTER (WHERE a_won), 0) AS longest_a,
+		COALESCE(MAX(length) FILTER (WHERE NOT a_won), 0) AS longest_b,
+		COALESCE((SELECT CASE WHEN a_won THEN length ELSE -length END FROM runs ORDER BY last_rn DESC LIMIT 1), 0) AS current
+	FROM runs`
+
+	if err := r.db.Raw(query, headToHeadArgs(playerAID, playerBID, matchType)).Scan(&streaks).Error; err != nil {
+		r.logger.Error("ошибка подсчёта серий во встречах игроков", "player_a_id", playerAID, "player_b_id", playerBID, "error", err)
+		return nil, err
+	}
+	return &streaks, nil
+}
+
+// GetHeadToHeadSeasons считает счёт встреч по сезонам, новые сезоны первыми.
+func (r *statsRepository) GetHeadToHeadSeasons(playerAID, playerBID uint, matchType string) ([]models.HeadToHeadSeason, error) {
+	seasons := []models.HeadToHeadSeason{}
+
+	query := headToHeadCTE + `
+	SELECT h.season_id, s.name AS season_name,
+		COUNT(*) AS total_matches,
+		COUNT(*) FILTER (WHERE h.a_won) AS player_a_wins,
+		COUNT(*) FILTER (WHERE NOT h.a_won) AS player_b_wins
+	FROM h
+	JOIN seasons s ON s.id = h.season_id
+	GROUP BY h.season_id, s.name, s.start_date
+	ORDER BY s.start_date DESC, h.season_id DESC`
+
+	if err := r.db.Raw(query, headToHeadArgs(playerAID, playerBID, matchType)).Scan(&seasons).Error; err != nil {
+		r.logger.Error("ошибка подсчёта встреч игроков по сезонам", "player_a_id", playerAID, "player_b_id", playerBID, "error", err)
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// calibrationEpsilon ограничивает прогноз в log-loss, чтобы уверенный промах не давал бесконечность.
+const calibrationEpsilon = 1e-15
+
+// GetCalibration сравнивает сохранённые в матчах прогнозы с исходами: для каждой из buckets
+// равных корзин прогноза считает число матчей, суммы прогнозов и исходов, квадратов ошибки
+// и log-loss. Прогноз берётся для стороны с меньшим id игрока; неявки и матчи без прогноза
+// (проведённые до появления рейтинговых систем) не учитываются.
+func (r *statsRepository) GetCalibration(filter models.CalibrationFilter, buckets int) ([]models.CalibrationBucketStats, error) {
+	var stats []models.CalibrationBucketStats
+
+	conditions := []string{
+		"m.deleted_at IS NULL",
+		"m.status = @confirmed",
+		"NOT m.forfeit",
+		"m.rating_engine <> ''",
+	}
+	args := map[string]interface{}{
+		"confirmed": models.MatchStatusConfirmed,
+		"singles":   models.MatchTypeSingles,
+		"buckets":   buckets,
+		"eps":       calibrationEpsilon,
+	}
+
+	if filter.SeasonID != nil {
+		conditions = append(conditions, "m.season_id = @season")
+		args["season"] = *filter.SeasonID
+	}
+	if filter.Engine != nil {
+		conditions = append(conditions, "m.rating_engine = @engine")
+		args["engine"] = *filter.Engine
+	}
+	if filter.Type != nil {
+		conditions = append(conditions, "COALESCE(NULLIF(m.type, ''), @singles) = @type")
+		args["type"] = *filter.Type
+	}
+	if filter.FromDate != nil {
+		conditions = append(conditions, "m.played_at >= @from")
+		args["from"] = *filter.FromDate
+	}
+	if filter.ToDate != nil {
+		conditions = append(conditions, "m.played_at <= @to")
+		args["to"] = *filter.ToDate
+	}
+
+	query := `
+	WITH p AS (
+		SELECT CASE WHEN m.winner_id < m.loser_id THEN m.winner_win_probability ELSE 1 - m.winner_win_probability END AS predicted,
+			CASE WHEN m.winner_id < m.loser_id THEN 1 ELSE 0 END AS outcome
+		FROM matches m
+		WHERE ` + strings.Join(conditions, "\n\t\t\tAND ") + `
+	)
+	SELECT LEAST(FLOOR(p.predicted * @buckets)::int, @buckets - 1) AS bucket,
+		COUNT(*) AS matches,
+		SUM(p.predicted) AS sum_predicted,
+		SUM(p.outcome) AS sum_outcome,
+		SUM((p.predicted - p.outcome) ^ 2) AS sum_squared_err,
+		SUM(-LN(CASE WHEN p.outcome = 1
+			THEN GREATEST(p.predicted, @eps)
+			ELSE GREATEST(1 - p.predicted, @eps) END)) AS sum_log_loss
+	FROM p
+	GROUP BY bucket
+	ORDER BY bucket`
+
+	if err := r.db.Raw(query, args).Scan(&stats).Error; err != nil {
+		r.logger.Error("ошибка подсчёта калибровки прогнозов", "error", err)
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"shumnaya/internal/config"
+	"shumnaya/internal/mailer"
+	"shumnaya/internal/models"
+	"shumnaya/internal/ratelimit"
+	"shumnaya/internal/repository"
+	"shumnaya/internal/utils/accounttoken"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidAccountToken  = errors.New("invalid, expired or already used link")
+	ErrEmailNotVerified     = errors.New("email is not verified, confirm it via the link sent on registration")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+)
+
+// AccountService — подтверждение email и восстановление пароля по ссылкам из писем.
+type AccountService interface {
+	SendVerificationEmail(player *models.Player) error
+	RequestEmailVerification(playerID uint) error
+	VerifyEmail(token string) error
+
+	RequestPasswordReset(email string) error
+	ResetPassword(token, newPassword string) error
+}
+
+type accountService struct {
+	db         *gorm.DB
+	logger     *slog.Logger
+	tokens     config.AccountTokenConfig
+	baseURL    string
+	mailer     mailer.Mailer
+	playerRepo repository.PlayerRepository
+	auth       AuthService
+	limiter    *ratelimit.Limiter
+	perEmail   ratelimit.Limit
+}
+
+func NewAccountService(
+	db *gorm.DB,
+	log *slog.Logger,
+	tokens config.AccountTokenConfig,
+	baseURL string,
+	m mailer.Mailer,
+	pr repository.PlayerRepository,
+	auth AuthService,
+	limiter *ratelimit.Limiter,
+	perEmail ratelimit.Limit,
+) AccountService {
+	return &accountService{
+		db: db, logger: log, tokens: tokens, baseURL: baseURL, mailer: m, playerRepo: pr, auth: auth,
+		limiter: limiter, perEmail: perEmail,
+	}
+}
+
+// SendVerificationEmail отправляет игроку ссылку подтверждения email.
+func (s *accountService) SendVerificationEmail(player *models.Player) error {
+	if player.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := accounttoken.Sign(s.tokens.Secret, accounttoken.PurposeVerifyEmail, player.ID, verifyState(player), s.tokens.VerifyTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      player.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы подтвердить email и записывать матчи, перейдите по ссылке:\n%s\n\nСсылка действует %s.\n",
+			player.Name, s.link("/verify-email", token), s.tokens.VerifyTTL,
+		),
+	})
+}
+
+// RequestEmailVerification повторно отправляет ссылку подтверждения авторизованному игроку.
+func (s *accountService) RequestEmailVerification(playerID uint) error {
+	if err := s.limiter.Allow("verify-email:player:"+strconv.FormatUint(uint64(playerID), 10), s.perEmail); err != nil {
+		return err
+	}
+
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return err
+	}
+	return s.SendVerificationEmail(player)
+}
+
+// VerifyEmail подтверждает email по ссылке. Повторно та же ссылка не сработает:
+// состояние учётной записи, под которое она подписана, уже изменилось.
+func (s *accountService) VerifyEmail(token string) error {
+	claims, err := accounttoken.Parse(token, accounttoken.PurposeVerifyEmail)
+	if err != nil {
+		return ErrInvalidAccountToken
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		playerRepoTx := s.playerRepo.WithDB(tx)
+
+		player, err := playerRepoTx.GetByIDForUpdate(claims.PlayerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidAccountToken
+			}
+			return err
+		}
+
+		if err := accounttoken.Verify(s.tokens.Secret, token, verifyState(player)); err != nil {
+			return ErrInvalidAccountToken
+		}
+
+		return playerRepoTx.MarkEmailVerified(player.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("service: email игрока подтверждён", "player_id", claims.PlayerID)
+	return nil
+}
+
+// RequestPasswordReset отправляет ссылку сброса пароля, если игрок с таким email есть.
+// Ответ одинаков для существующих и несуществующих адресов, чтобы по нему нельзя было
+// перебирать зарегистрированные email. Лимит по email проверяется до поиска игрока по той же причине.
+func (s *accountService) RequestPasswordReset(email string) error {
+	if err := s.limiter.Allow("password-reset:email:"+normalizeEmail(email), s.perEmail); err != nil {
+		return err
+	}
+
+	player, err := s.playerRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := accounttoken.Sign(s.tokens.Secret, accounttoken.PurposeResetPassword, player.ID, player.PasswordHash, s.tokens.ResetTTL)
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(mailer.Message{
+		To:      player.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка действует %s. Если вы не запрашивали сброс, просто проигнорируйте письмо.\n",
+			player.Name, s.link("/reset-password", token), s.tokens.ResetTTL,
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("service: отправлена ссылка сброса пароля", "player_id", player.ID)
+	return nil
+}
+
+// ResetPassword задаёт новый пароль по ссылке и завершает все сессии игрока.
+// Ссылка подписана под прежний хеш пароля, поэтому после сброса она недействительна.
+// Переход по ссылке из письма заодно подтверждает email.
+func (s *accountService) ResetPassword(token, newPassword string) error {
+	claims, err := accounttoken.Parse(token, accounttoken.PurposeResetPassword)
+	if err != nil {
+		return ErrInvalidAccountToken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		playerRepoTx := s.playerRepo.WithDB(tx)
+
+		player, err := playerRepoTx.GetByIDForUpdate(claims.PlayerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidAccountToken
+			}
+			return err
+		}
+
+		if err := accounttoken.Verify(s.tokens.Secret, token, player.PasswordHash); err != nil {
+			return ErrInvalidAccountToken
+		}
+
+		if err := playerRepoTx.UpdatePassword(player.ID, string(hash)); err != nil {
+			return err
+		}
+		if !player.EmailVerified {
+			return playerRepoTx.MarkEmailVerified(player.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.auth.LogoutAll(claims.PlayerID); err != nil {
+		return err
+	}
+
+	s.logger.Info("service: пароль игрока сброшен", "player_id", claims.PlayerID)
+	return nil
+}
+
+func (s *accountService) link(path, token string) string {
+	return s.baseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// verifyState — состояние, под которое подписана ссылка подтверждения: после подтверждения
+// или смены email оно другое.
+func verifyState(player *models.Player) string {
+	return player.Email + ":" + strconv.FormatBool(player.EmailVerified)
+}
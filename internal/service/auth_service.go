@@ -0,0 +1,183 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"shumnaya/internal/config"
+	"shumnaya/internal/models"
+	"shumnaya/internal/repository"
+	"shumnaya/internal/utils/jwtkeys"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used, the session was revoked")
+)
+
+// AuthService выдаёт пары токенов и ведёт refresh-токены: ротацию при каждом обновлении,
+// выход и обнаружение повторного использования.
+type AuthService interface {
+	IssueTokens(player *models.Player) (*models.AuthTokens, error)
+	Refresh(refreshToken string) (*models.AuthTokens, error)
+	Logout(refreshToken string) error
+	LogoutAll(playerID uint) error
+}
+
+type authService struct {
+	db         *gorm.DB
+	logger     *slog.Logger
+	cfg        config.TokenConfig
+	keys       *jwtkeys.Manager
+	playerRepo repository.PlayerRepository
+	tokenRepo  repository.RefreshTokenRepository
+}
+
+func NewAuthService(db *gorm.DB, log *slog.Logger, cfg config.TokenConfig, keys *jwtkeys.Manager, pr repository.PlayerRepository, tr repository.RefreshTokenRepository) AuthService {
+	return &authService{db: db, logger: log, cfg: cfg, keys: keys, playerRepo: pr, tokenRepo: tr}
+}
+
+// IssueTokens начинает новую сессию игрока (новую семью refresh-токенов).
+func (s *authService) IssueTokens(player *models.Player) (*models.AuthTokens, error) {
+	familyID, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(s.tokenRepo, player, familyID)
+}
+
+// Refresh обменивает refresh-токен на новую пару. Каждый refresh-токен действует один раз:
+// повторное предъявление уже обменянного токена означает, что его украли, поэтому
+// отзывается вся семья — и у злоумышленника, и у владельца.
+func (s *authService) Refresh(refreshToken string) (*models.AuthTokens, error) {
+	var tokens *models.AuthTokens
+	var reusedFamily string
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		tokenRepoTx := s.tokenRepo.WithDB(tx)
+
+		stored, err := tokenRepoTx.GetByHashForUpdate(hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		now := time.Now()
+		switch {
+		case stored.RevokedAt != nil, now.After(stored.ExpiresAt):
+			return ErrInvalidRefreshToken
+		case stored.UsedAt != nil:
+			// Отзыв семьи сохраняется отдельно: эта транзакция откатится вместе с ошибкой
+			reusedFamily = stored.FamilyID
+			return ErrRefreshTokenReused
+		}
+
+		// Роль перечитывается, чтобы её смена вступала в силу при обновлении токена
+		player, err := s.playerRepo.WithDB(tx).GetByID(stored.PlayerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if err := tokenRepoTx.MarkUsed(stored.ID, now); err != nil {
+			return err
+		}
+
+		tokens, err = s.issue(tokenRepoTx, player, stored.FamilyID)
+		return err
+	})
+
+	if errors.Is(err, ErrRefreshTokenReused) {
+		s.logger.Warn("service: повторное использование refresh-токена, семья отозвана", "family_id", reusedFamily)
+		if revokeErr := s.tokenRepo.RevokeFamily(reusedFamily, time.Now()); revokeErr != nil {
+			return nil, revokeErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Logout завершает сессию, к которой относится refresh-токен. Уже выданный
+// access-токен действует до истечения своего короткого срока.
+func (s *authService) Logout(refreshToken string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		tokenRepoTx := s.tokenRepo.WithDB(tx)
+
+		stored, err := tokenRepoTx.GetByHashForUpdate(hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		return tokenRepoTx.RevokeFamily(stored.FamilyID, time.Now())
+	})
+}
+
+// LogoutAll завершает все сессии игрока на всех устройствах.
+func (s *authService) LogoutAll(playerID uint) error {
+	if err := s.tokenRepo.RevokeByPlayer(playerID, time.Now()); err != nil {
+		return err
+	}
+
+	s.logger.Info("service: все сессии игрока завершены", "player_id", playerID)
+	return nil
+}
+
+// issue выпускает access-токен и следующий refresh-токен семьи familyID.
+func (s *authService) issue(tokenRepo repository.RefreshTokenRepository, player *models.Player, familyID string) (*models.AuthTokens, error) {
+	accessToken, err := s.keys.Sign(player.ID, player.Role, s.cfg.AccessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tokenRepo.Create(&models.RefreshToken{
+		PlayerID:  player.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.cfg.AccessTTL,
+	}, nil
+}
+
+// randomToken — 32 случайных байта в base64url.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
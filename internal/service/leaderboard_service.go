@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/repository"
+	"shumnaya/internal/utils/elo"
+
+	"gorm.io/gorm"
+)
+
+type LeaderboardService interface {
+	GetLeaderboard(filter models.LeaderboardFilter, page models.PageRequest, callerID uint) (*models.LeaderboardPage, error)
+}
+
+type leaderboardService struct {
+	logger          *slog.Logger
+	leaderboardRepo repository.LeaderboardRepository
+}
+
+func NewLeaderboardService(log *slog.Logger, lr repository.LeaderboardRepository) LeaderboardService {
+	return &leaderboardService{logger: log, leaderboardRepo: lr}
+}
+
+// GetLeaderboard возвращает страницу рейтинг-листа. Для авторизованного игрока
+// (callerID не 0) его строка помечается на странице и возвращается в Me, даже если
+// на эту страницу не попала; Me пустой, если игрок не проходит фильтры.
+func (s *leaderboardService) GetLeaderboard(filter models.LeaderboardFilter, page models.PageRequest, callerID uint) (*models.LeaderboardPage, error) {
+	result, err := s.leaderboardRepo.GetPage(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Data {
+		markEntry(&result.Data[i], callerID)
+	}
+
+	if callerID != 0 {
+		me, err := s.leaderboardRepo.GetEntry(filter, callerID)
+		switch {
+		case err == nil:
+			markEntry(me, callerID)
+			result.Me = me
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func markEntry(entry *models.LeaderboardEntry, callerID uint) {
+	entry.Provisional = entry.Games < elo.ProvisionalGames
+	entry.IsMe = callerID != 0 && entry.PlayerID == callerID
+}
@@ -0,0 +1,543 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/repository"
+	"shumnaya/internal/utils/elo"
+	"shumnaya/internal/utils/rating"
+
+	"gorm.io/gorm"
+)
+
+type standingKey struct {
+	playerID  uint
+	seasonID  uint
+	matchType string
+}
+
+// ledger держит в памяти рейтинги игроков и строки турнирной таблицы, которые
+// затрагивает проведение (или повторное проведение) матчей, и сохраняет их одним flush.
+// Все изменения рейтинга и таблицы проходят через apply/revert, поэтому запись нового
+// матча и пересчёт истории дают одинаковый результат.
+type ledger struct {
+	playerRepo   repository.PlayerRepository
+	standingRepo repository.StandingRepository
+	seasonRepo   repository.SeasonRepository
+	historyRepo  repository.RatingHistoryRepository
+
+	players   map[uint]*models.Player
+	standings map[standingKey]*models.Standing
+	seasons   map[uint]*models.Season
+
+	// history — загруженные записи истории рейтинга откатываемых матчей,
+	// replaced — матчи, чьи записи будут удалены, written — новые записи.
+	history  map[uint][]models.RatingHistory
+	replaced []uint
+	written  []models.RatingHistory
+
+	// fresh — история проводится с нуля: строк таблицы в базе не ищем, а заводим пустые.
+	fresh bool
+}
+
+// participant — игрок одной из сторон матча вместе с его строкой таблицы
+// и полем матча, в которое записывается изменение его рейтинга.
+type participant struct {
+	player   *models.Player
+	standing *models.Standing
+	change   *int
+}
+
+func newLedger(pr repository.PlayerRepository, sr repository.StandingRepository, ssr repository.SeasonRepository, hr repository.RatingHistoryRepository) *ledger {
+	return &ledger{
+		playerRepo:   pr,
+		standingRepo: sr,
+		seasonRepo:   ssr,
+		historyRepo:  hr,
+		players:      make(map[uint]*models.Player),
+		standings:    make(map[standingKey]*models.Standing),
+		seasons:      make(map[uint]*models.Season),
+		history:      make(map[uint][]models.RatingHistory),
+	}
+}
+
+// loadHistory загружает историю рейтинга матчей, которые будут откатываться.
+func (l *ledger) loadHistory(matches []models.Match) error {
+	ids := make([]uint, len(matches))
+	for i := range matches {
+		ids[i] = matches[i].ID
+	}
+
+	entries, err := l.historyRepo.GetByMatchIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		l.history[e.MatchID] = append(l.history[e.MatchID], e)
+	}
+	return nil
+}
+
+// startFresh готовит ledger к проведению всей истории с нуля: рейтинги игроков
+// сбрасываются к начальным, таблицы сезонов начинаются с пустых строк.
+func (l *ledger) startFresh(players []models.Player, seasons []models.Season) {
+	l.fresh = true
+
+	for i := range players {
+		p := &players[i]
+		p.Rating = rating.InitialRating
+		p.RatingDeviation = rating.InitialDeviation
+		p.RatingVolatility = rating.InitialVolatility
+		l.players[p.ID] = p
+	}
+
+	for i := range seasons {
+		l.seasons[seasons[i].ID] = &seasons[i]
+	}
+}
+
+// takeHistory отдаёт накопленные записи истории рейтинга для сохранения по частям.
+func (l *ledger) takeHistory() []models.RatingHistory {
+	written := l.written
+	l.written = nil
+	return written
+}
+
+func (l *ledger) player(id uint) (*models.Player, error) {
+	if p, ok := l.players[id]; ok {
+		return p, nil
+	}
+
+	p, err := l.playerRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	l.players[id] = p
+	return p, nil
+}
+
+func (l *ledger) season(id uint) (*models.Season, error) {
+	if season, ok := l.seasons[id]; ok {
+		return season, nil
+	}
+
+	season, err := l.seasonRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	l.seasons[id] = season
+	return season, nil
+}
+
+func (l *ledger) standing(playerID, seasonID uint, matchType string) (*models.Standing, error) {
+	key := standingKey{playerID: playerID, seasonID: seasonID, matchType: matchType}
+	if st, ok := l.standings[key]; ok {
+		return st, nil
+	}
+
+	var st *models.Standing
+	if l.fresh {
+		st = &models.Standing{PlayerID: playerID, SeasonID: seasonID, Type: matchType}
+	} else {
+		var err error
+		st, err = l.standingRepo.GetByPlayerAndSeason(playerID, seasonID, matchType)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			st = &models.Standing{PlayerID: playerID, SeasonID: seasonID, Type: matchType}
+		}
+	}
+
+	l.standings[key] = st
+	return st, nil
+}
+
+// startSeasonRating заводит сезонный рейтинг игрока по политике сброса сезона,
+// если он ещё не заведён: первый матч игрока в сезоне (или разряде) либо строка
+// таблицы, созданная до появления сезонных рейтингов.
+func (l *ledger) startSeasonRating(p participant, seasonID uint) error {
+	if p.standing.RatingDeviation != 0 {
+		return nil
+	}
+
+	season, err := l.season(seasonID)
+	if err != nil {
+		return err
+	}
+
+	start, err := rating.SeasonStart(season.RatingReset, season.RatingRegressPercent, rating.Player{
+		Rating:     float64(p.player.Rating),
+		Deviation:  p.player.RatingDeviation,
+		Volatility: p.player.RatingVolatility,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.standing.Rating = int(math.Round(start.Rating))
+	p.standing.RatingDeviation = start.Deviation
+	p.standing.RatingVolatility = start.Volatility
+	return nil
+}
+
+// side собирает участников победившей или проигравшей стороны матча.
+func (l *ledger) side(m *models.Match, winners bool) ([]participant, error) {
+	ids := []uint{m.LoserID}
+	changes := []*int{&m.LoserRatingChange}
+	if m.LoserPartnerID != nil {
+		ids = append(ids, *m.LoserPartnerID)
+		changes = append(changes, &m.LoserPartnerRatingChange)
+	}
+
+	if winners {
+		ids = []uint{m.WinnerID}
+		changes = []*int{&m.WinnerRatingChange}
+		if m.WinnerPartnerID != nil {
+			ids = append(ids, *m.WinnerPartnerID)
+			changes = append(changes, &m.WinnerPartnerRatingChange)
+		}
+	}
+
+	side := make([]participant, len(ids))
+	for i, id := range ids {
+		p, err := l.player(id)
+		if err != nil {
+			return nil, err
+		}
+		st, err := l.standing(id, m.SeasonID, matchTypeOf(m))
+		if err != nil {
+			return nil, err
+		}
+		side[i] = participant{player: p, standing: st, change: changes[i]}
+	}
+
+	return side, nil
+}
+
+// apply проводит матч через рейтинговую систему сезона: записывает в match изменения
+// рейтинга и прогноз на победу, обновляет рейтинги игроков, таблицу сезона и историю рейтинга.
+func (l *ledger) apply(m *models.Match) error {
+	season, err := l.season(m.SeasonID)
+	if err != nil {
+		return err
+	}
+
+	engine, err := rating.New(season.RatingEngine, rating.Options{TeamRating: season.DoublesTeamRating})
+	if err != nil {
+		return err
+	}
+
+	winners, err := l.side(m, true)
+	if err != nil {
+		return err
+	}
+	losers, err := l.side(m, false)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range append(winners, losers...) {
+		if err := l.startSeasonRating(p, m.SeasonID); err != nil {
+			return err
+		}
+	}
+
+	winnerBefore := teamRating(winners, season.DoublesTeamRating)
+	loserBefore := teamRating(losers, season.DoublesTeamRating)
+	m.WinnerRatingBefore = &winnerBefore
+	m.LoserRatingBefore = &loserBefore
+
+	if m.Forfeit {
+		// Неявка идёт только в таблицу, рейтинги не меняются
+		m.RatingEngine = ""
+		m.WinnerWinProbability = 0
+		for _, p := range append(winners, losers...) {
+			*p.change = 0
+		}
+	} else {
+		winnerStates := ratingStates(winners)
+		loserStates := ratingStates(losers)
+
+		m.RatingEngine = engine.Name()
+		m.WinnerWinProbability = engine.WinProbability(winnerStates, loserStates)
+
+		// Общий и сезонный рейтинги считаются одной системой, но независимо друг от друга
+		ratedWinners, ratedLosers := engine.Rate(winnerStates, loserStates)
+		seasonWinners, seasonLosers := engine.Rate(seasonRatingStates(winners), seasonRatingStates(losers))
+
+		l.rate(m, engine.Name(), winners, ratedWinners, seasonWinners)
+		l.rate(m, engine.Name(), losers, ratedLosers, seasonLosers)
+	}
+
+	// Очки сохраняются в матче, чтобы откат вычитал ровно начисленное
+	m.WinnerStandingPoints, m.LoserStandingPoints = standingPoints(season.Points, m)
+
+	for _, p := range winners {
+		p.standing.Wins += 1
+		p.standing.Points += m.WinnerStandingPoints
+	}
+
+	for _, p := range losers {
+		p.standing.Losses += 1
+		p.standing.Points += m.LoserStandingPoints
+	}
+
+	return nil
+}
+
+// standingPoints считает очки в таблицу для каждого игрока победившей и проигравшей
+// стороны по схеме сезона. Прогноз на победу в матче должен быть уже посчитан.
+func standingPoints(scheme models.PointsScheme, m *models.Match) (winner, loser int) {
+	if m.Forfeit {
+		return scheme.ForfeitWinPoints(), scheme.ForfeitLoss
+	}
+
+	winner = scheme.WinPoints() + scheme.Participation
+	loser = scheme.Loss + scheme.Participation
+
+	if m.WinnerGames > 0 && m.LoserGames == 0 {
+		winner += scheme.SweepBonus
+	}
+	if m.WinnerWinProbability < 0.5 {
+		winner += scheme.UpsetBonus
+	}
+
+	return winner, loser
+}
+
+// revert откатывает ранее проведённый матч. Если по матчу есть история рейтинга,
+// игрокам возвращается состояние до матча, иначе (старые записи) вычитается
+// сохранённое в матче изменение рейтинга.
+func (l *ledger) revert(m *models.Match) error {
+	winners, err := l.side(m, true)
+	if err != nil {
+		return err
+	}
+	losers, err := l.side(m, false)
+	if err != nil {
+		return err
+	}
+
+	before := make(map[uint]models.RatingHistory)
+	for _, e := range l.history[m.ID] {
+		before[e.PlayerID] = e
+	}
+	l.replaced = append(l.replaced, m.ID)
+
+	for _, p := range append(winners, losers...) {
+		if e, ok := before[p.player.ID]; ok {
+			p.player.Rating = e.RatingBefore
+			p.player.RatingDeviation = e.DeviationBefore
+			p.player.RatingVolatility = e.VolatilityBefore
+			p.standing.Rating = e.SeasonRatingBefore
+			p.standing.RatingDeviation = e.SeasonDeviationBefore
+			p.standing.RatingVolatility = e.SeasonVolatilityBefore
+			continue
+		}
+		p.player.Rating -= *p.change
+	}
+
+	for _, p := range winners {
+		p.standing.Wins -= 1
+		p.standing.Points -= m.WinnerStandingPoints
+	}
+
+	for _, p := range losers {
+		p.standing.Losses -= 1
+		p.standing.Points -= m.LoserStandingPoints
+	}
+
+	// Если в сезоне у игрока не осталось матчей, сезонный рейтинг заведётся заново
+	// от общего рейтинга на момент следующего матча.
+	for _, p := range append(winners, losers...) {
+		if p.standing.Wins+p.standing.Losses == 0 {
+			p.standing.Rating = 0
+			p.standing.RatingDeviation = 0
+			p.standing.RatingVolatility = 0
+		}
+	}
+
+	return nil
+}
+
+// rate переносит посчитанные системой общие и сезонные рейтинги на игроков и пишет историю.
+func (l *ledger) rate(m *models.Match, engine string, side []participant, rated, seasonRated []rating.Player) {
+	for i, p := range side {
+		entry := models.RatingHistory{
+			PlayerID:         p.player.ID,
+			MatchID:          m.ID,
+			SeasonID:         m.SeasonID,
+			Engine:           engine,
+			RatingBefore:     p.player.Rating,
+			DeviationBefore:  p.player.RatingDeviation,
+			VolatilityBefore: p.player.RatingVolatility,
+			PlayedAt:         m.PlayedAt,
+
+			SeasonRatingBefore:     p.standing.Rating,
+			SeasonDeviationBefore:  p.standing.RatingDeviation,
+			SeasonVolatilityBefore: p.standing.RatingVolatility,
+		}
+
+		p.player.Rating = int(math.Round(rated[i].Rating))
+		p.player.RatingDeviation = rated[i].Deviation
+		p.player.RatingVolatility = rated[i].Volatility
+		*p.change = p.player.Rating - entry.RatingBefore
+
+		entry.RatingAfter = p.player.Rating
+		entry.DeviationAfter = p.player.RatingDeviation
+		entry.VolatilityAfter = p.player.RatingVolatility
+
+		p.standing.Rating = int(math.Round(seasonRated[i].Rating))
+		p.standing.RatingDeviation = seasonRated[i].Deviation
+		p.standing.RatingVolatility = seasonRated[i].Volatility
+
+		entry.SeasonRatingAfter = p.standing.Rating
+		entry.SeasonDeviationAfter = p.standing.RatingDeviation
+		entry.SeasonVolatilityAfter = p.standing.RatingVolatility
+		l.written = append(l.written, entry)
+	}
+}
+
+// teamRating — общий рейтинг стороны перед матчем: рейтинг игрока или пары по правилу сезона.
+func teamRating(side []participant, mode string) int {
+	ratings := make([]int, len(side))
+	for i, p := range side {
+		ratings[i] = p.player.Rating
+	}
+	return elo.TeamRating(ratings, mode)
+}
+
+func ratingStates(side []participant) []rating.Player {
+	states := make([]rating.Player, len(side))
+	for i, p := range side {
+		states[i] = rating.Player{
+			Rating:     float64(p.player.Rating),
+			Deviation:  p.player.RatingDeviation,
+			Volatility: p.player.RatingVolatility,
+			Games:      p.standing.Wins + p.standing.Losses,
+			Wins:       p.standing.Wins,
+		}
+	}
+	return states
+}
+
+func seasonRatingStates(side []participant) []rating.Player {
+	states := make([]rating.Player, len(side))
+	for i, p := range side {
+		states[i] = rating.Player{
+			Rating:     float64(p.standing.Rating),
+			Deviation:  p.standing.RatingDeviation,
+			Volatility: p.standing.RatingVolatility,
+			Games:      p.standing.Wins + p.standing.Losses,
+			Wins:       p.standing.Wins,
+		}
+	}
+	return states
+}
+
+// matchTypeOf возвращает разряд матча; у старых записей он не заполнен и означает одиночный матч.
+func matchTypeOf(m *models.Match) string {
+	if m.Type == "" {
+		return models.MatchTypeSingles
+	}
+	return m.Type
+}
+
+// flush сохраняет все затронутые рейтинги, строки таблицы и историю рейтинга.
+// Порядок записи фиксирован, чтобы параллельные транзакции не ловили взаимные блокировки.
+func (l *ledger) flush() error {
+	if err := l.historyRepo.DeleteByMatchIDs(l.replaced); err != nil {
+		return err
+	}
+	if err := l.historyRepo.CreateBatch(l.written); err != nil {
+		return err
+	}
+
+	playerIDs := make([]uint, 0, len(l.players))
+	for id := range l.players {
+		playerIDs = append(playerIDs, id)
+	}
+	sort.Slice(playerIDs, func(i, j int) bool { return playerIDs[i] < playerIDs[j] })
+
+	// Только рейтинговые столбцы: снимок игрока взят в начале транзакции, и полное
+	// сохранение затёрло бы параллельную смену пароля, роли или подтверждение email
+	players := make([]*models.Player, 0, len(playerIDs))
+	for _, id := range playerIDs {
+		players = append(players, l.players[id])
+	}
+	if err := l.playerRepo.UpdateRatings(players); err != nil {
+		return err
+	}
+
+	for _, st := range sortedStandings(l.standings) {
+		if err := l.standingRepo.CreateOrUpdate(st); err != nil {
+			return err
+		}
+	}
+
+	return l.recalculateRanks()
+}
+
+// recalculateRanks пересчитывает места во всех таблицах, которых коснулись матчи.
+func (l *ledger) recalculateRanks() error {
+	type table struct {
+		seasonID  uint
+		matchType string
+	}
+
+	done := make(map[table]bool)
+	week := rankWeek(time.Now())
+
+	for _, st := range sortedStandings(l.standings) {
+		t := table{seasonID: st.SeasonID, matchType: st.Type}
+		if done[t] {
+			continue
+		}
+		done[t] = true
+
+		season, err := l.season(st.SeasonID)
+		if err != nil {
+			return err
+		}
+		tieBreakers, err := ParseTieBreakers(season.TieBreakers)
+		if err != nil {
+			return err
+		}
+		if err := l.standingRepo.RecalculateRanks(st.SeasonID, st.Type, tieBreakers, week); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortedStandings возвращает строки таблиц в порядке сезона, разряда и игрока.
+func sortedStandings(standings map[standingKey]*models.Standing) []*models.Standing {
+	keys := make([]standingKey, 0, len(standings))
+	for key := range standings {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].seasonID != keys[j].seasonID {
+			return keys[i].seasonID < keys[j].seasonID
+		}
+		if keys[i].matchType != keys[j].matchType {
+			return keys[i].matchType < keys[j].matchType
+		}
+		return keys[i].playerID < keys[j].playerID
+	})
+
+	sorted := make([]*models.Standing, len(keys))
+	for i, key := range keys {
+		sorted[i] = standings[key]
+	}
+	return sorted
+}
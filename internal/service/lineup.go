@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"shumnaya/internal/models"
+)
+
+var ErrInvalidLineup = errors.New("invalid match lineup")
+
+// lineup — составы сторон матча. В одиночном матче партнёров нет,
+// в парном они указаны у обеих сторон.
+type lineup struct {
+	winnerID        uint
+	loserID         uint
+	winnerPartnerID *uint
+	loserPartnerID  *uint
+}
+
+func newLineup(winnerID, loserID uint, winnerPartnerID, loserPartnerID *uint) (lineup, error) {
+	l := lineup{
+		winnerID:        winnerID,
+		loserID:         loserID,
+		winnerPartnerID: winnerPartnerID,
+		loserPartnerID:  loserPartnerID,
+	}
+
+	if (winnerPartnerID == nil) != (loserPartnerID == nil) {
+		return l, fmt.Errorf("%w: a doubles match needs a partner on both sides", ErrInvalidLineup)
+	}
+
+	seen := make(map[uint]bool)
+	for _, id := range l.playerIDs() {
+		if seen[id] {
+			return l, fmt.Errorf("%w: player %d appears more than once", ErrInvalidLineup, id)
+		}
+		seen[id] = true
+	}
+
+	return l, nil
+}
+
+func (l lineup) matchType() string {
+	if l.winnerPartnerID != nil {
+		return models.MatchTypeDoubles
+	}
+	return models.MatchTypeSingles
+}
+
+func (l lineup) playerIDs() []uint {
+	ids := []uint{l.winnerID, l.loserID}
+	if l.winnerPartnerID != nil {
+		ids = append(ids, *l.winnerPartnerID)
+	}
+	if l.loserPartnerID != nil {
+		ids = append(ids, *l.loserPartnerID)
+	}
+	return ids
+}
+
+func (l lineup) applyTo(m *models.Match) {
+	m.WinnerID = l.winnerID
+	m.LoserID = l.loserID
+	m.WinnerPartnerID = l.winnerPartnerID
+	m.LoserPartnerID = l.loserPartnerID
+	m.Type = l.matchType()
+}
+
+// sideOf сообщает, на чьей стороне играл игрок: победителей (true) или проигравших (false).
+// ok == false, если игрок в матче не участвовал.
+func sideOf(m *models.Match, playerID uint) (winner bool, ok bool) {
+	switch {
+	case m.WinnerID == playerID, m.WinnerPartnerID != nil && *m.WinnerPartnerID == playerID:
+		return true, true
+	case m.LoserID == playerID, m.LoserPartnerID != nil && *m.LoserPartnerID == playerID:
+		return false, true
+	}
+	return false, false
+}
@@ -3,21 +3,33 @@ package service
 import (
 	"errors"
 	"log/slog"
+	"strings"
 	"time"
 
 	"shumnaya/internal/models"
 	"shumnaya/internal/repository"
-	"shumnaya/internal/utils/elo"
+	"shumnaya/internal/utils/score"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrNotMatchParticipant = errors.New("player is not a participant of the match")
+	ErrMatchNotPending     = errors.New("match is not awaiting confirmation")
+	ErrReporterCannotReply = errors.New("the reporting side cannot confirm or dispute its own result")
+)
+
 type MatchService interface {
-	RecordMatch(winnerID, loserID, seasonID uint, score string) (*models.Match, error)
+	RecordMatch(reporterID uint, req *models.CreateMatchRequest) (*models.Match, error)
+	ConfirmMatch(id, playerID uint) (*models.Match, error)
+	DisputeMatch(id, playerID uint, reason string) (*models.Match, error)
+	AutoConfirmExpired(timeout time.Duration) (int, error)
+	ConfirmPendingInSeason(seasonID uint) (int, error)
+	UpdateMatch(id uint, actor models.Actor, req *models.UpdateMatchRequest) (*models.Match, error)
+	DeleteMatch(id uint, actor models.Actor) error
 
 	Get() ([]models.Match, error)
-	GetFiltered(filter *models.MatchFilter) ([]models.Match, error)
-	GetHeadToHead(playerAID, playerBID uint, limit int) (*models.HeadToHeadRecord, error)
+	GetFiltered(filter *models.MatchFilter, page models.PageRequest) (*models.MatchPage, error)
 }
 
 type matchService struct {
@@ -26,115 +38,469 @@ type matchService struct {
 	matchRepo    repository.MatchRepository
 	playerRepo   repository.PlayerRepository
 	standingRepo repository.StandingRepository
+	seasonRepo   repository.SeasonRepository
+	historyRepo  repository.RatingHistoryRepository
 }
 
-func NewMatchService(db *gorm.DB, log *slog.Logger, mr repository.MatchRepository, pr repository.PlayerRepository, sr repository.StandingRepository) MatchService {
-	return &matchService{db: db, logger: log, matchRepo: mr, playerRepo: pr, standingRepo: sr}
+func NewMatchService(
+	db *gorm.DB,
+	log *slog.Logger,
+	mr repository.MatchRepository,
+	pr repository.PlayerRepository,
+	sr repository.StandingRepository,
+	ssr repository.SeasonRepository,
+	hr repository.RatingHistoryRepository,
+) MatchService {
+	return &matchService{db: db, logger: log, matchRepo: mr, playerRepo: pr, standingRepo: sr, seasonRepo: ssr, historyRepo: hr}
 }
 
-func (s *matchService) RecordMatch(winnerID, loserID, seasonID uint, score string) (*models.Match, error) {
-	if winnerID == loserID {
-		return nil, errors.New("winner and loser cannot be the same")
+// RecordMatch сохраняет результат, заявленный одним из участников. Матч ждёт подтверждения
+// соперника и до этого не влияет на рейтинги и таблицу.
+func (s *matchService) RecordMatch(reporterID uint, req *models.CreateMatchRequest) (*models.Match, error) {
+	players, err := newLineup(req.WinnerID, req.LoserID, req.WinnerPartnerID, req.LoserPartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Записывать матчи могут только игроки с подтверждённым email
+	reporter, err := s.playerRepo.GetByID(reporterID)
+	if err != nil {
+		return nil, err
+	}
+	if !reporter.EmailVerified {
+		return nil, ErrEmailNotVerified
 	}
 
+	seasonID := req.SeasonID
+	rawScore := req.Score
+
 	var created *models.Match
 
-	err := s.db.Transaction(func(tx *gorm.DB) error {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
 		// Репозитории в контексте транзакции
 		matchRepoTx := s.matchRepo.WithDB(tx)
-		standingRepoTx := s.standingRepo.WithDB(tx)
 
-		// Проверка существования сезона
-		if err := tx.First(&models.Season{}, seasonID).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return errors.New("season not found")
+		// Матч записывается только в активный сезон и в его даты
+		season, err := s.openSeason(tx, seasonID)
+		if err != nil {
+			return err
+		}
+		playedAt := time.Now()
+		if !season.IsActive {
+			return ErrSeasonNotActive
+		}
+		if playedAt.Before(season.StartDate) || playedAt.After(season.EndDate) {
+			return ErrOutsideSeasonDates
+		}
+
+		result, scoreText, err := matchResult(rawScore, req.Forfeit, season)
+		if err != nil {
+			s.logger.Warn("некорректный счёт матча", "score", rawScore, "season_id", seasonID, "error", err)
+			return err
+		}
+
+		for _, id := range players.playerIDs() {
+			if _, err := s.playerRepo.WithDB(tx).GetByID(id); err != nil {
+				return err
 			}
+		}
+
+		match := &models.Match{
+			SeasonID:     seasonID,
+			Forfeit:      req.Forfeit,
+			Score:        scoreText,
+			WinnerGames:  result.WinnerGames,
+			LoserGames:   result.LoserGames,
+			Games:        matchGames(result),
+			PlayedAt:     playedAt,
+			Venue:        strings.TrimSpace(req.Venue),
+			Status:       models.MatchStatusPending,
+			ReportedByID: &reporterID,
+		}
+		players.applyTo(match)
+
+		if _, ok := sideOf(match, reporterID); !ok {
+			return ErrNotMatchParticipant
+		}
+
+		if err := matchRepoTx.Create(match); err != nil {
 			return err
 		}
 
-		var winner models.Player
-		if err := tx.First(&winner, winnerID).Error; err != nil {
+		created = match
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("матч ожидает подтверждения", "match_id", created.ID, "reported_by", reporterID)
+	return created, nil
+}
+
+// ConfirmMatch подтверждает результат от имени соперника заявившего игрока
+// и проводит матч через Elo и таблицу.
+func (s *matchService) ConfirmMatch(id, playerID uint) (*models.Match, error) {
+	var confirmed *models.Match
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.matchRepo.WithDB(tx).LockLedger(); err != nil {
+			return err
+		}
+		match, err := s.matchRepo.WithDB(tx).GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if err := checkReply(match, playerID); err != nil {
+			return err
+		}
+		if _, err := s.openSeason(tx, match.SeasonID); err != nil {
 			return err
 		}
 
-		var loser models.Player
-		if err := tx.First(&loser, loserID).Error; err != nil {
+		if err := s.confirm(tx, match); err != nil {
 			return err
 		}
 
-		wStanding, err := standingRepoTx.GetByPlayerAndSeason(winnerID, seasonID)
-		if err != nil && err != gorm.ErrRecordNotFound {
+		confirmed, err = s.matchRepo.WithDB(tx).GetByID(id)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("матч подтверждён", "match_id", id, "player_id", playerID)
+	return confirmed, nil
+}
+
+// DisputeMatch отклоняет заявленный результат. Оспоренный матч не влияет на рейтинги,
+// пока его не исправят через UpdateMatch.
+func (s *matchService) DisputeMatch(id, playerID uint, reason string) (*models.Match, error) {
+	var disputed *models.Match
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		matchRepoTx := s.matchRepo.WithDB(tx)
+
+		match, err := matchRepoTx.GetByIDForUpdate(id)
+		if err != nil {
 			return err
 		}
-		if wStanding == nil || err == gorm.ErrRecordNotFound {
-			wStanding = &models.Standing{PlayerID: winnerID, SeasonID: seasonID}
+		if err := checkReply(match, playerID); err != nil {
+			return err
+		}
+		if _, err := s.openSeason(tx, match.SeasonID); err != nil {
+			return err
 		}
 
-		lStanding, err := standingRepoTx.GetByPlayerAndSeason(loserID, seasonID)
-		if err != nil && err != gorm.ErrRecordNotFound {
+		match.Status = models.MatchStatusDisputed
+		match.DisputeReason = reason
+
+		if err := matchRepoTx.Update(match); err != nil {
 			return err
 		}
-		if lStanding == nil || err == gorm.ErrRecordNotFound {
-			lStanding = &models.Standing{PlayerID: loserID, SeasonID: seasonID}
+
+		disputed = match
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("результат матча оспорен", "match_id", id, "player_id", playerID)
+	return disputed, nil
+}
+
+// AutoConfirmExpired подтверждает матчи, которые ждут ответа соперника дольше timeout.
+func (s *matchService) AutoConfirmExpired(timeout time.Duration) (int, error) {
+	pending, err := s.matchRepo.GetPendingBefore(time.Now().Add(-timeout))
+	if err != nil {
+		s.logger.Error("ошибка получения неподтверждённых матчей", "error", err)
+		return 0, err
+	}
+
+	return s.autoConfirm(pending), nil
+}
+
+// ConfirmPendingInSeason подтверждает все неподтверждённые матчи закончившегося сезона,
+// не дожидаясь срока автоподтверждения, чтобы сезон можно было закрыть.
+func (s *matchService) ConfirmPendingInSeason(seasonID uint) (int, error) {
+	pending, err := s.matchRepo.GetPendingBySeason(seasonID)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.autoConfirm(pending), nil
+}
+
+// autoConfirm подтверждает матчи по одному в хронологическом порядке; ошибка на одном
+// матче не мешает остальным. Возвращает число подтверждённых.
+func (s *matchService) autoConfirm(pending []models.Match) int {
+	confirmed := 0
+	for _, stale := range pending {
+		id := stale.ID
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := s.matchRepo.WithDB(tx).LockLedger(); err != nil {
+				return err
+			}
+			// Список получен вне транзакции: матч могли уже подтвердить или оспорить
+			match, err := s.matchRepo.WithDB(tx).GetByIDForUpdate(id)
+			if err != nil {
+				return err
+			}
+			if _, err := s.openSeason(tx, match.SeasonID); err != nil {
+				return err
+			}
+			return s.confirm(tx, match)
+		})
+		if errors.Is(err, ErrMatchNotPending) {
+			continue
+		}
+		if err != nil {
+			s.logger.Error("ошибка автоподтверждения матча", "match_id", id, "error", err)
+			continue
 		}
 
-		wGames := wStanding.Wins + wStanding.Losses
-		lGames := lStanding.Wins + lStanding.Losses
+		s.logger.Info("матч подтверждён автоматически", "match_id", id)
+		confirmed++
+	}
 
-		winnerNew := elo.NewRating(winner.Rating, loser.Rating, true, wGames, wStanding.Wins)
-		loserNew := elo.NewRating(loser.Rating, winner.Rating, false, lGames, lStanding.Wins)
+	return confirmed
+}
 
-		winnerChange := winnerNew - winner.Rating
-		loserChange := loserNew - loser.Rating
+// confirm переводит матч в подтверждённые и встраивает его в историю: если после него
+// уже подтверждены другие матчи, они пересчитываются вместе с ним. match должен быть
+// прочитан в той же транзакции через GetByIDForUpdate, иначе его могут провести дважды.
+// Как и replayFrom, вызывается только под LockLedger.
+func (s *matchService) confirm(tx *gorm.DB, match *models.Match) error {
+	if match.Status != models.MatchStatusPending {
+		return ErrMatchNotPending
+	}
 
-		match := &models.Match{
-			WinnerID:           winnerID,
-			LoserID:            loserID,
-			SeasonID:           seasonID,
-			Score:              score,
-			WinnerRatingChange: winnerChange,
-			LoserRatingChange:  loserChange,
-			PlayedAt:           time.Now(),
+	now := time.Now()
+	match.Status = models.MatchStatusConfirmed
+	match.ConfirmedAt = &now
+
+	return s.replayFrom(tx, match, match.ID, func(matches []models.Match) []models.Match {
+		return append([]models.Match{*match}, matches...)
+	})
+}
+
+// checkReply проверяет, что отвечает игрок с противоположной заявившему стороны.
+func checkReply(match *models.Match, playerID uint) error {
+	side, ok := sideOf(match, playerID)
+	if !ok {
+		return ErrNotMatchParticipant
+	}
+	if match.ReportedByID != nil {
+		reporterSide, _ := sideOf(match, *match.ReportedByID)
+		if reporterSide == side {
+			return ErrReporterCannotReply
 		}
+	}
+	if match.Status != models.MatchStatusPending {
+		return ErrMatchNotPending
+	}
+	return nil
+}
 
-		if err := matchRepoTx.Create(match); err != nil {
+// UpdateMatch исправляет результат матча. Для подтверждённого матча он и все последующие
+// подтверждённые матчи откатываются и проводятся заново в хронологическом порядке, поэтому
+// рейтинги и таблицы получаются такими, как если бы матч изначально был записан верно.
+// Исправлять матч может администратор или организатор его сезона (и нового сезона, если он меняется).
+func (s *matchService) UpdateMatch(id uint, actor models.Actor, req *models.UpdateMatchRequest) (*models.Match, error) {
+	players, err := newLineup(req.WinnerID, req.LoserID, req.WinnerPartnerID, req.LoserPartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	seasonID := req.SeasonID
+	rawScore := req.Score
+
+	var updated *models.Match
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		matchRepoTx := s.matchRepo.WithDB(tx)
+
+		if err := matchRepoTx.LockLedger(); err != nil {
+			return err
+		}
+		original, err := matchRepoTx.GetByIDForUpdate(id)
+		if err != nil {
 			return err
 		}
 
-		winner.Rating = winnerNew
-		loser.Rating = loserNew
-
-		if err := tx.Save(&winner).Error; err != nil {
+		// Ни исходный, ни новый сезон матча не должны быть закрыты
+		originalSeason, err := s.openSeason(tx, original.SeasonID)
+		if err != nil {
 			return err
 		}
-		if err := tx.Save(&loser).Error; err != nil {
+		season, err := s.openSeason(tx, seasonID)
+		if err != nil {
 			return err
 		}
+		if !actor.CanManageSeason(originalSeason) || !actor.CanManageSeason(season) {
+			return ErrNotSeasonOrganizer
+		}
 
-		wStanding.Wins += 1
-		wStanding.Points += 1
+		result, scoreText, err := matchResult(rawScore, req.Forfeit, season)
+		if err != nil {
+			s.logger.Warn("некорректный счёт матча", "match_id", id, "score", rawScore, "error", err)
+			return err
+		}
 
-		lStanding.Losses += 1
+		for _, playerID := range players.playerIDs() {
+			if _, err := s.playerRepo.WithDB(tx).GetByID(playerID); err != nil {
+				return err
+			}
+		}
+
+		edit := func(m *models.Match) {
+			players.applyTo(m)
+			m.SeasonID = seasonID
+			m.Forfeit = req.Forfeit
+			m.Score = scoreText
+			m.WinnerGames = result.WinnerGames
+			m.LoserGames = result.LoserGames
+			m.Venue = strings.TrimSpace(req.Venue)
+		}
 
-		if err := standingRepoTx.CreateOrUpdate(wStanding); err != nil {
+		if original.Status == models.MatchStatusConfirmed {
+			err = s.replayFrom(tx, original, id, func(matches []models.Match) []models.Match {
+				for i := range matches {
+					if matches[i].ID == id {
+						edit(&matches[i])
+					}
+				}
+				return matches
+			})
+		} else {
+			// Неподтверждённый матч ещё не проведён через рейтинги: достаточно исправить его,
+			// оспоренный результат после исправления снова ждёт подтверждения.
+			edit(original)
+			original.Status = models.MatchStatusPending
+			original.DisputeReason = ""
+			err = matchRepoTx.Update(original)
+		}
+		if err != nil {
 			return err
 		}
-		if err := standingRepoTx.CreateOrUpdate(lStanding); err != nil {
+
+		if err := matchRepoTx.ReplaceGames(id, matchGames(result)); err != nil {
 			return err
 		}
 
-		created = match
-		return nil
+		updated, err = matchRepoTx.GetByID(id)
+		return err
 	})
 
 	if err != nil {
 		return nil, err
 	}
-	return created, nil
+
+	s.logger.Info("матч исправлен", "match_id", id)
+	return updated, nil
+}
+
+// DeleteMatch аннулирует матч и пересчитывает все последующие матчи без него.
+// Аннулировать матч может администратор или организатор его сезона.
+func (s *matchService) DeleteMatch(id uint, actor models.Actor) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		matchRepoTx := s.matchRepo.WithDB(tx)
+
+		if err := matchRepoTx.LockLedger(); err != nil {
+			return err
+		}
+		original, err := matchRepoTx.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+		season, err := s.openSeason(tx, original.SeasonID)
+		if err != nil {
+			return err
+		}
+		if !actor.CanManageSeason(season) {
+			return ErrNotSeasonOrganizer
+		}
+
+		if original.Status == models.MatchStatusConfirmed {
+			err = s.replayFrom(tx, original, 0, func(matches []models.Match) []models.Match {
+				kept := matches[:0]
+				for _, m := range matches {
+					if m.ID != id {
+						kept = append(kept, m)
+					}
+				}
+				return kept
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return matchRepoTx.Delete(id)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("матч аннулирован", "match_id", id)
+	return nil
 }
 
-func (s *matchService) GetFiltered(filter *models.MatchFilter) ([]models.Match, error) {
-	return s.matchRepo.GetFiltered(filter)
+// replayFrom откатывает все подтверждённые матчи начиная с from (включительно, по played_at и id),
+// даёт edit изменить этот отрезок истории и заново проводит его через рейтинговую систему сезона.
+// Новые изменения рейтинга сохраняются пакетно в каждом матче отрезка; матч editedID,
+// который edit изменил целиком (0 — такого нет), сохраняется полностью. Транзакция tx
+// должна держать LockLedger: игроки и таблицы читаются без блокировок строк.
+func (s *matchService) replayFrom(tx *gorm.DB, from *models.Match, editedID uint, edit func([]models.Match) []models.Match) error {
+	matchRepoTx := s.matchRepo.WithDB(tx)
+
+	matches, err := matchRepoTx.GetPlayedSince(from.PlayedAt, from.ID)
+	if err != nil {
+		return err
+	}
+
+	led := newLedger(s.playerRepo.WithDB(tx), s.standingRepo.WithDB(tx), s.seasonRepo.WithDB(tx), s.historyRepo.WithDB(tx))
+
+	if err := led.loadHistory(matches); err != nil {
+		return err
+	}
+
+	for i := len(matches) - 1; i >= 0; i-- {
+		if err := led.revert(&matches[i]); err != nil {
+			return err
+		}
+	}
+
+	matches = edit(matches)
+
+	for i := range matches {
+		if err := led.apply(&matches[i]); err != nil {
+			return err
+		}
+		if editedID != 0 && matches[i].ID == editedID {
+			if err := matchRepoTx.Update(&matches[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := matchRepoTx.UpdateRatingChanges(matches); err != nil {
+		return err
+	}
+
+	s.logger.Info("история матчей пересчитана", "from_match_id", from.ID, "matches", len(matches))
+
+	return led.flush()
+}
+
+func (s *matchService) GetFiltered(filter *models.MatchFilter, page models.PageRequest) (*models.MatchPage, error) {
+	return s.matchRepo.GetFiltered(filter, page)
 }
 
 func (s *matchService) Get() ([]models.Match, error) {
@@ -157,32 +523,54 @@ func (s *matchService) Get() ([]models.Match, error) {
 	return matches, nil
 }
 
-func (s *matchService) GetHeadToHead(playerAID, playerBID uint, limit int) (*models.HeadToHeadRecord, error) {
-	var record models.HeadToHeadRecord
-
-	record.PlayerAID = playerAID
-	record.PlayerBID = playerBID
-
-	totalMatches, playerAWins, playerBWins, err := s.matchRepo.HeadToHeadRecordMatchesCount(playerAID, playerBID)
+// openSeason загружает сезон матча, не давая закрыть его до конца транзакции,
+// и проверяет, что он ещё не закрыт: итоги закрытого сезона изменять нельзя.
+func (s *matchService) openSeason(tx *gorm.DB, seasonID uint) (*models.Season, error) {
+	season, err := s.seasonRepo.WithDB(tx).GetByIDForShare(seasonID)
 	if err != nil {
 		return nil, err
 	}
-	
-	record.TotalMatches = int(totalMatches)
-	
-	record.PlayerAWins = int(playerAWins)
-	
-	record.PlayerBWins = int(playerBWins)
+	if season.IsClosed() {
+		return nil, ErrSeasonClosed
+	}
+	return season, nil
+}
 
-	if limit <= 0 && limit > (int(totalMatches)) {
-		limit = 5
+// matchResult разбирает счёт матча по формату сезона. У матча, засчитанного за неявку,
+// счёта по партиям нет.
+func matchResult(raw string, forfeit bool, season *models.Season) (*score.Result, string, error) {
+	if forfeit {
+		return &score.Result{}, models.ForfeitScore, nil
 	}
 
-	recentMatches, err := s.matchRepo.HeadToHeadRecentMatches(playerAID, playerBID, limit)
+	result, err := score.Parse(raw, seasonScoreRules(season))
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	record.LastMatchesPlayed = recentMatches
+	return result, result.String(), nil
+}
 
-	return &record, nil
-}
\ No newline at end of file
+// seasonScoreRules переводит формат сезона в правила валидации счёта.
+// Нулевые значения (сезоны, созданные до появления формата) заменяются значениями по умолчанию.
+func seasonScoreRules(season *models.Season) score.Rules {
+	rules := score.DefaultRules()
+	if season.BestOf > 0 {
+		rules.BestOf = season.BestOf
+	}
+	if season.GamePoints > 0 {
+		rules.PointsToWin = season.GamePoints
+	}
+	return rules
+}
+
+func matchGames(result *score.Result) []models.MatchGame {
+	games := make([]models.MatchGame, len(result.Games))
+	for i, g := range result.Games {
+		games[i] = models.MatchGame{
+			Number:       i + 1,
+			WinnerPoints: g.WinnerPoints,
+			LoserPoints:  g.LoserPoints,
+		}
+	}
+	return games
+}
@@ -3,21 +3,30 @@ package service
 import (
 	"errors"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 
+	"shumnaya/internal/config"
 	"shumnaya/internal/models"
+	"shumnaya/internal/ratelimit"
 	"shumnaya/internal/repository"
-	"shumnaya/internal/utils"
+	"shumnaya/internal/utils/rating"
 
 	"gorm.io/gorm"
 )
 
+var ErrInvalidRole = errors.New("invalid role, expected player, organizer or admin")
+
 type PlayerService interface {
-	GetPlayerProfile(id uint) (*models.PlayerProfile, error)
-	RegisterPlayer(name, email, password string) (string, error)
+	GetPlayerProfile(id uint, matchType string) (*models.PlayerProfile, error)
+	SearchPlayers(query string, page models.PageRequest) (*models.PlayerSummaryPage, error)
+	SetRole(id uint, role string) error
+	GrantAdmins(ids []uint)
+	RegisterPlayer(name, email, password, clientIP string) (*models.AuthTokens, error)
 
-	Login(email, password string) (string, error)
+	Login(email, password, clientIP string) (*models.AuthTokens, error)
 }
 
 const defaultRecentMatchesLimit = 5
@@ -27,20 +36,39 @@ type playerService struct {
 	logger     *slog.Logger
 	playerRepo repository.PlayerRepository
 	matchRepo  repository.MatchRepository
+	auth       AuthService
+	account    AccountService
+	limiter    *ratelimit.Limiter
+	limits     config.RateLimitConfig
 }
 
-func NewPlayerService(db *gorm.DB, log *slog.Logger, pr repository.PlayerRepository, mr repository.MatchRepository) PlayerService {
-	return &playerService{db: db, logger: log, playerRepo: pr, matchRepo: mr}
+func NewPlayerService(
+	db *gorm.DB,
+	log *slog.Logger,
+	pr repository.PlayerRepository,
+	mr repository.MatchRepository,
+	auth AuthService,
+	account AccountService,
+	limiter *ratelimit.Limiter,
+	limits config.RateLimitConfig,
+) PlayerService {
+	return &playerService{db: db, logger: log, playerRepo: pr, matchRepo: mr, auth: auth, account: account, limiter: limiter, limits: limits}
 }
 
 func (s *playerService) RegisterPlayer(
 	name string,
 	email string,
 	password string,
-) (string, error) {
+	clientIP string,
+) (*models.AuthTokens, error) {
+
+	// Лимит по IP ставит middleware; здесь — по email, чтобы один адрес не перебирали с разных IP
+	if err := s.limiter.Allow("register:email:"+normalizeEmail(email), s.limits.AccountPerEmail); err != nil {
+		return nil, err
+	}
 
 	if _, err := s.playerRepo.GetByEmail(email); err == nil {
-		return "", errors.New("игрок с таким email уже существует")
+		return nil, errors.New("игрок с таким email уже существует")
 	}
 
 	hash, err := bcrypt.GenerateFromPassword(
@@ -54,14 +82,17 @@ func (s *playerService) RegisterPlayer(
 				"error", err,
 			)
 		}
-		return "", err
+		return nil, err
 	}
 
 	player := &models.Player{
-		Name:         name,
-		Email:        email,
-		PasswordHash: string(hash),
-		Rating:       1000,
+		Name:             name,
+		Email:            email,
+		PasswordHash:     string(hash),
+		Role:             models.RolePlayer,
+		Rating:           rating.InitialRating,
+		RatingDeviation:  rating.InitialDeviation,
+		RatingVolatility: rating.InitialVolatility,
 	}
 
 	if err := s.playerRepo.Create(player); err != nil {
@@ -72,18 +103,20 @@ func (s *playerService) RegisterPlayer(
 				"error", err,
 			)
 		}
-		return "", err
+		return nil, err
 	}
 
-	token, err := utils.GenerateJWT(int64(player.ID))
-	if err != nil {
-		return "", err
+	// Письмо не должно мешать регистрации: ссылку можно запросить повторно
+	if err := s.account.SendVerificationEmail(player); err != nil {
+		s.logger.Warn("service: не удалось отправить письмо подтверждения email", "player_id", player.ID, "error", err)
 	}
 
-	return token, nil
+	return s.auth.IssueTokens(player)
 }
 
-func (s *playerService) GetPlayerProfile(id uint) (*models.PlayerProfile, error) {
+// GetPlayerProfile собирает профиль игрока. matchType ("singles", "doubles" или пустая строка
+// для всех матчей) ограничивает общий счёт и последние матчи; разбивка по разрядам есть всегда.
+func (s *playerService) GetPlayerProfile(id uint, matchType string) (*models.PlayerProfile, error) {
 	if id == 0 {
 		return nil, errors.New("invalid player id")
 	}
@@ -94,59 +127,112 @@ func (s *playerService) GetPlayerProfile(id uint) (*models.PlayerProfile, error)
 		return nil, err
 	}
 
-	matches, err := s.matchRepo.GetByPlayerID(id)
+	summaries, err := s.matchRepo.CountPlayerResults(id)
 	if err != nil {
-		s.logger.Error("service: failed to get player matches", "player_id", id, "error", err)
+		s.logger.Error("service: failed to count player results", "player_id", id, "error", err)
 		return nil, err
 	}
 
-	recentMatches, err := s.matchRepo.GetRecentByPlayerID(id, defaultRecentMatchesLimit)
+	recentMatches, err := s.matchRepo.GetRecentByPlayerID(id, matchType, defaultRecentMatchesLimit)
 	if err != nil {
 		s.logger.Error("service: failed to get recent player matches", "player_id", id, "error", err)
 		return nil, err
 	}
 
-	total := len(matches)
-	wins := 0
-	for _, m := range matches {
+	singles := summaries[models.MatchTypeSingles]
+	doubles := summaries[models.MatchTypeDoubles]
 
-		if m.WinnerID == id {
-			wins++
-		}
+	total := models.MatchSummary{
+		TotalMatches: singles.TotalMatches + doubles.TotalMatches,
+		Wins:         singles.Wins + doubles.Wins,
+		Losses:       singles.Losses + doubles.Losses,
+	}
+	switch matchType {
+	case models.MatchTypeSingles:
+		total = singles
+	case models.MatchTypeDoubles:
+		total = doubles
 	}
-
-	losses := total - wins
 
 	profile := &models.PlayerProfile{
 		Player:        *player,
 		Rating:        player.Rating,
-		TotalMatches:  total,
-		Wins:          wins,
-		Losses:        losses,
+		TotalMatches:  total.TotalMatches,
+		Wins:          total.Wins,
+		Losses:        total.Losses,
+		Singles:       singles,
+		Doubles:       doubles,
 		RecentMatches: recentMatches,
 	}
 
 	return profile, nil
 }
 
-func (s *playerService) Login(email, password string) (string, error) {
+// Login проверяет пароль и выдаёт токены. Неудачные попытки считаются по email и по IP;
+// после серии неудач ключ блокируется, и каждая следующая неудача удваивает блокировку.
+// Блокировка и лимиты возвращаются как *ratelimit.LimitError.
+func (s *playerService) Login(email, password, clientIP string) (*models.AuthTokens, error) {
+	emailKey := "login:email:" + normalizeEmail(email)
+	ipKey := "login:ip:" + clientIP
+
+	if err := s.limiter.CheckLock(emailKey); err != nil {
+		return nil, err
+	}
+	if err := s.limiter.CheckLock(ipKey); err != nil {
+		return nil, err
+	}
+	if err := s.limiter.Allow(emailKey, s.limits.LoginPerEmail); err != nil {
+		return nil, err
+	}
+
 	player, err := s.playerRepo.GetByEmail(email)
+	if err == nil {
+		err = bcrypt.CompareHashAndPassword(
+			[]byte(player.PasswordHash),
+			[]byte(password),
+		)
+	}
 	if err != nil {
-		return "", errors.New("неверный email или пароль")
+		// Несуществующий email считается так же, как неверный пароль: иначе по блокировкам
+		// можно было бы узнать, какие адреса зарегистрированы
+		s.limiter.Fail(emailKey, s.limits.LoginLockout)
+		s.limiter.Fail(ipKey, s.limits.LoginIPLockout)
+		return nil, errors.New("неверный email или пароль")
 	}
 
-	err = bcrypt.CompareHashAndPassword(
-		[]byte(player.PasswordHash),
-		[]byte(password),
-	)
-	if err != nil {
-		return "", errors.New("неверный email или пароль")
+	s.limiter.Reset(emailKey)
+	return s.auth.IssueTokens(player)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// SearchPlayers ищет игроков по началу имени или похожему имени; пустой query — все игроки.
+func (s *playerService) SearchPlayers(query string, page models.PageRequest) (*models.PlayerSummaryPage, error) {
+	return s.playerRepo.Search(strings.TrimSpace(query), page)
+}
+
+// SetRole назначает игроку роль. Новая роль действует со следующего обновления токена.
+func (s *playerService) SetRole(id uint, role string) error {
+	if !slices.Contains(models.Roles, role) {
+		return ErrInvalidRole
 	}
 
-	token, err := utils.GenerateJWT(int64(player.ID))
-	if err != nil {
-		return "", err
+	if err := s.playerRepo.UpdateRole(id, role); err != nil {
+		return err
 	}
 
-	return token, nil
+	s.logger.Info("service: роль игрока изменена", "player_id", id, "role", role)
+	return nil
+}
+
+// GrantAdmins выдаёт роль администратора игрокам из конфигурации при запуске,
+// чтобы на новой базе было кому назначать остальные роли.
+func (s *playerService) GrantAdmins(ids []uint) {
+	for _, id := range ids {
+		if err := s.SetRole(id, models.RoleAdmin); err != nil {
+			s.logger.Warn("service: не удалось выдать роль администратора", "player_id", id, "error", err)
+		}
+	}
 }
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/repository"
+	"shumnaya/internal/utils/rating"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultCalibrationBuckets = 10
+	MaxCalibrationBuckets     = 50
+)
+
+type PredictionService interface {
+	Predict(playerAID, playerBID uint, seasonID *uint) (*models.MatchPrediction, error)
+	GetCalibration(filter models.CalibrationFilter, buckets int) (*models.CalibrationReport, error)
+}
+
+type predictionService struct {
+	logger       *slog.Logger
+	playerRepo   repository.PlayerRepository
+	seasonRepo   repository.SeasonRepository
+	standingRepo repository.StandingRepository
+	statsRepo    repository.StatsRepository
+}
+
+func NewPredictionService(log *slog.Logger, pr repository.PlayerRepository, ssr repository.SeasonRepository, sr repository.StandingRepository, str repository.StatsRepository) PredictionService {
+	return &predictionService{logger: log, playerRepo: pr, seasonRepo: ssr, standingRepo: sr, statsRepo: str}
+}
+
+// Predict прогнозирует одиночный матч игроков A и B так, как его провела бы запись
+// результата: рейтинговой системой сезона seasonID (по умолчанию активного сезона,
+// без активного сезона — Elo) по общим рейтингам и сезонному счёту игроков.
+func (s *predictionService) Predict(playerAID, playerBID uint, seasonID *uint) (*models.MatchPrediction, error) {
+	if playerAID == playerBID {
+		return nil, ErrSamePlayer
+	}
+
+	playerA, err := s.playerRepo.GetByID(playerAID)
+	if err != nil {
+		return nil, err
+	}
+	playerB, err := s.playerRepo.GetByID(playerBID)
+	if err != nil {
+		return nil, err
+	}
+
+	season, err := s.predictionSeason(seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var engineName, teamRating string
+	if season != nil {
+		engineName, teamRating = season.RatingEngine, season.DoublesTeamRating
+	}
+	engine, err := rating.New(engineName, rating.Options{TeamRating: teamRating})
+	if err != nil {
+		return nil, err
+	}
+
+	stateA, err := s.ratingState(playerA, season)
+	if err != nil {
+		return nil, err
+	}
+	stateB, err := s.ratingState(playerB, season)
+	if err != nil {
+		return nil, err
+	}
+
+	a := []rating.Player{stateA}
+	b := []rating.Player{stateB}
+	probability := engine.WinProbability(a, b)
+
+	aWon, bLost := engine.Rate(a, b)
+	bWon, aLost := engine.Rate(b, a)
+
+	prediction := &models.MatchPrediction{
+		PlayerAID:             playerAID,
+		PlayerBID:             playerBID,
+		Engine:                engine.Name(),
+		PlayerARating:         playerA.Rating,
+		PlayerBRating:         playerB.Rating,
+		PlayerAWinProbability: math.Round(probability*1000) / 1000,
+		PlayerBWinProbability: math.Round((1-probability)*1000) / 1000,
+		IfPlayerAWins: models.RatingSwing{
+			PlayerAChange: ratingChange(playerA.Rating, aWon[0]),
+			PlayerBChange: ratingChange(playerB.Rating, bLost[0]),
+		},
+		IfPlayerBWins: models.RatingSwing{
+			PlayerAChange: ratingChange(playerA.Rating, aLost[0]),
+			PlayerBChange: ratingChange(playerB.Rating, bWon[0]),
+		},
+	}
+	if season != nil {
+		prediction.SeasonID = &season.ID
+	}
+
+	return prediction, nil
+}
+
+// predictionSeason возвращает сезон, по правилам которого считается прогноз, или nil,
+// если сезон не указан и активного сезона нет.
+func (s *predictionService) predictionSeason(seasonID *uint) (*models.Season, error) {
+	if seasonID != nil {
+		return s.seasonRepo.GetByID(*seasonID)
+	}
+
+	season, err := s.seasonRepo.GetActive()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return season, err
+}
+
+// ratingState — состояние игрока перед матчем, как его собирает ledger: общий рейтинг
+// и счёт игрока в одиночном разряде сезона.
+func (s *predictionService) ratingState(p *models.Player, season *models.Season) (rating.Player, error) {
+	state := rating.Player{
+		Rating:     float64(p.Rating),
+		Deviation:  p.RatingDeviation,
+		Volatility: p.RatingVolatility,
+	}
+	if season == nil {
+		return state, nil
+	}
+
+	st, err := s.standingRepo.GetByPlayerAndSeason(p.ID, season.ID, models.MatchTypeSingles)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return state, nil
+		}
+		return state, err
+	}
+
+	state.Games = st.Wins + st.Losses
+	state.Wins = st.Wins
+	return state, nil
+}
+
+func ratingChange(before int, after rating.Player) int {
+	return int(math.Round(after.Rating)) - before
+}
+
+// GetCalibration проверяет сохранённые в матчах прогнозы на их исходах: Brier score,
+// log-loss и калибровку по buckets корзинам прогноза (по умолчанию DefaultCalibrationBuckets,
+// не больше MaxCalibrationBuckets).
+func (s *predictionService) GetCalibration(filter models.CalibrationFilter, buckets int) (*models.CalibrationReport, error) {
+	if buckets <= 0 {
+		buckets = DefaultCalibrationBuckets
+	}
+	buckets = min(buckets, MaxCalibrationBuckets)
+
+	stats, err := s.statsRepo.GetCalibration(filter, buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CalibrationReport{Buckets: make([]models.CalibrationBucket, buckets)}
+	width := 1 / float64(buckets)
+	for i := range report.Buckets {
+		report.Buckets[i] = models.CalibrationBucket{
+			From: round3(float64(i) * width),
+			To:   round3(float64(i+1) * width),
+		}
+	}
+
+	var squaredErr, logLoss float64
+	for _, b := range stats {
+		bucket := &report.Buckets[b.Bucket]
+		bucket.Matches = b.Matches
+		bucket.MeanPredicted = round3(b.SumPredicted / float64(b.Matches))
+		bucket.ObservedRate = round3(b.SumOutcome / float64(b.Matches))
+
+		report.Matches += b.Matches
+		squaredErr += b.SumSquaredErr
+		logLoss += b.SumLogLoss
+	}
+
+	if report.Matches > 0 {
+		brier := squaredErr / float64(report.Matches)
+		report.BrierScore = round3(brier)
+		// Прогноз 50/50 даёт Brier score 0.25 при любых исходах
+		report.BrierSkill = round3(1 - brier/0.25)
+		report.LogLoss = round3(logLoss / float64(report.Matches))
+	}
+
+	s.logger.Info("service: калибровка прогнозов посчитана", "matches", report.Matches, "brier_score", report.BrierScore)
+
+	return report, nil
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
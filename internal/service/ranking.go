@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"shumnaya/internal/models"
+)
+
+var ErrInvalidTieBreakers = errors.New("invalid tie breakers")
+
+// ParseTieBreakers разбирает список критериев сезона. Пустой список (сезоны, созданные
+// до появления настройки) означает критерии по умолчанию.
+func ParseTieBreakers(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = models.DefaultTieBreakers
+	}
+
+	var criteria []string
+	for _, c := range strings.Split(raw, ",") {
+		c = strings.TrimSpace(c)
+		if !slices.Contains(models.TieBreakers, c) {
+			return nil, fmt.Errorf("%w: unknown criterion %q", ErrInvalidTieBreakers, c)
+		}
+		if slices.Contains(criteria, c) {
+			return nil, fmt.Errorf("%w: duplicate criterion %q", ErrInvalidTieBreakers, c)
+		}
+		criteria = append(criteria, c)
+	}
+
+	return criteria, nil
+}
+
+// rankWeek возвращает начало недели (понедельник), к которой относится снимок мест.
+func rankWeek(t time.Time) time.Time {
+	days := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-days, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"log/slog"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/repository"
+)
+
+type RatingService interface {
+	GetRatingHistory(playerID uint, filter *models.RatingHistoryFilter) (*models.RatingTimeline, error)
+}
+
+type ratingService struct {
+	logger      *slog.Logger
+	playerRepo  repository.PlayerRepository
+	historyRepo repository.RatingHistoryRepository
+}
+
+func NewRatingService(log *slog.Logger, pr repository.PlayerRepository, hr repository.RatingHistoryRepository) RatingService {
+	return &ratingService{logger: log, playerRepo: pr, historyRepo: hr}
+}
+
+// GetRatingHistory строит временной ряд рейтинга игрока по истории матчей и находит
+// пик и минимум за выбранный период.
+func (s *ratingService) GetRatingHistory(playerID uint, filter *models.RatingHistoryFilter) (*models.RatingTimeline, error) {
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.historyRepo.GetByPlayer(playerID, filter)
+	if err != nil {
+		s.logger.Error("service: ошибка получения истории рейтинга", "player_id", playerID, "error", err)
+		return nil, err
+	}
+
+	timeline := &models.RatingTimeline{
+		PlayerID:      playerID,
+		CurrentRating: player.Rating,
+		Points:        make([]models.RatingPoint, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		timeline.Points = append(timeline.Points, models.RatingPoint{
+			PlayedAt:     e.PlayedAt,
+			MatchID:      e.MatchID,
+			SeasonID:     e.SeasonID,
+			Engine:       e.Engine,
+			RatingBefore: e.RatingBefore,
+			Rating:       e.RatingAfter,
+			Change:       e.RatingAfter - e.RatingBefore,
+			SeasonRating: e.SeasonRatingAfter,
+		})
+
+		if timeline.Peak == nil || e.RatingAfter > timeline.Peak.Rating {
+			timeline.Peak = &models.RatingExtreme{Rating: e.RatingAfter, MatchID: e.MatchID, PlayedAt: e.PlayedAt}
+		}
+		if timeline.Lowest == nil || e.RatingAfter < timeline.Lowest.Rating {
+			timeline.Lowest = &models.RatingExtreme{Rating: e.RatingAfter, MatchID: e.MatchID, PlayedAt: e.PlayedAt}
+		}
+	}
+
+	return timeline, nil
+}
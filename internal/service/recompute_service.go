@@ -0,0 +1,232 @@
+package service
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultRecomputeBatchSize = 5000
+	MaxRecomputeBatchSize     = 10000
+)
+
+type RecomputeOptions struct {
+	// DryRun — посчитать и вернуть отчёт, ничего не сохраняя
+	DryRun bool
+	// BatchSize — сколько матчей читается и сохраняется за один запрос
+	BatchSize int
+}
+
+type RecomputeService interface {
+	Recompute(opts RecomputeOptions) (*models.RecomputeReport, error)
+}
+
+type recomputeService struct {
+	db           *gorm.DB
+	logger       *slog.Logger
+	matchRepo    repository.MatchRepository
+	playerRepo   repository.PlayerRepository
+	standingRepo repository.StandingRepository
+	seasonRepo   repository.SeasonRepository
+	historyRepo  repository.RatingHistoryRepository
+}
+
+func NewRecomputeService(
+	db *gorm.DB,
+	log *slog.Logger,
+	mr repository.MatchRepository,
+	pr repository.PlayerRepository,
+	sr repository.StandingRepository,
+	ssr repository.SeasonRepository,
+	hr repository.RatingHistoryRepository,
+) RecomputeService {
+	return &recomputeService{db: db, logger: log, matchRepo: mr, playerRepo: pr, standingRepo: sr, seasonRepo: ssr, historyRepo: hr}
+}
+
+// Recompute заново проводит все подтверждённые матчи в хронологическом порядке через
+// текущие правила рейтинга: пересобирает рейтинги игроков, изменения рейтинга в матчах,
+// историю рейтинга и турнирные таблицы. Матчи читаются и сохраняются пачками, в памяти
+// держатся только игроки и строки таблиц.
+//
+// Всё выполняется в одной транзакции; на время пересчёта запись матчей блокируется.
+func (s *recomputeService) Recompute(opts RecomputeOptions) (*models.RecomputeReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultRecomputeBatchSize
+	}
+	if opts.BatchSize > MaxRecomputeBatchSize {
+		opts.BatchSize = MaxRecomputeBatchSize
+	}
+
+	report := &models.RecomputeReport{DryRun: opts.DryRun, StartedAt: time.Now()}
+
+	s.logger.Info("пересчёт рейтингов запущен", "dry_run", opts.DryRun, "batch_size", opts.BatchSize)
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if !opts.DryRun {
+			if err := s.matchRepo.WithDB(tx).LockLedger(); err != nil {
+				return err
+			}
+			if err := tx.Exec("LOCK TABLE matches IN EXCLUSIVE MODE").Error; err != nil {
+				return err
+			}
+		}
+
+		matchRepoTx := s.matchRepo.WithDB(tx)
+		historyRepoTx := s.historyRepo.WithDB(tx)
+
+		players, err := s.playerRepo.WithDB(tx).GetAllRatings()
+		if err != nil {
+			return err
+		}
+		seasons, err := s.seasonRepo.WithDB(tx).GetAll()
+		if err != nil {
+			return err
+		}
+
+		oldRatings := make(map[uint]int, len(players))
+		for _, p := range players {
+			oldRatings[p.ID] = p.Rating
+		}
+
+		led := newLedger(s.playerRepo.WithDB(tx), s.standingRepo.WithDB(tx), s.seasonRepo.WithDB(tx), historyRepoTx)
+		led.startFresh(players, seasons)
+
+		if !opts.DryRun {
+			if err := historyRepoTx.DeleteAll(); err != nil {
+				return err
+			}
+		}
+
+		var lastPlayedAt time.Time
+		var lastID uint
+
+		for {
+			matches, err := matchRepoTx.GetConfirmedPage(lastPlayedAt, lastID, opts.BatchSize)
+			if err != nil {
+				return err
+			}
+			if len(matches) == 0 {
+				break
+			}
+
+			changed := make([]models.Match, 0, len(matches))
+			for i := range matches {
+				before := matches[i]
+				if err := led.apply(&matches[i]); err != nil {
+					return err
+				}
+				if ledgerChanged(&before, &matches[i]) {
+					changed = append(changed, matches[i])
+				}
+			}
+
+			report.Matches += len(matches)
+			report.MatchesChanged += len(changed)
+
+			history := led.takeHistory()
+			if !opts.DryRun {
+				if err := matchRepoTx.UpdateRatingChanges(changed); err != nil {
+					return err
+				}
+				if err := historyRepoTx.CreateBatch(history); err != nil {
+					return err
+				}
+			}
+
+			last := matches[len(matches)-1]
+			lastPlayedAt, lastID = last.PlayedAt, last.ID
+
+			s.logger.Info("пересчёт: пачка матчей проведена", "processed", report.Matches, "changed", report.MatchesChanged)
+		}
+
+		standings := sortedStandings(led.standings)
+		report.Standings = len(standings)
+
+		for i := range players {
+			p := &players[i]
+			old := oldRatings[p.ID]
+			if p.Rating == old {
+				continue
+			}
+			report.Players = append(report.Players, models.PlayerRatingDiff{
+				PlayerID:  p.ID,
+				Name:      p.Name,
+				OldRating: old,
+				NewRating: p.Rating,
+				Delta:     p.Rating - old,
+			})
+		}
+		report.PlayersChanged = len(report.Players)
+
+		if opts.DryRun {
+			return nil
+		}
+
+		// Отклонение и волатильность меняются у всех сыгравших, поэтому сохраняются все игроки.
+		all := make([]*models.Player, len(players))
+		for i := range players {
+			all[i] = &players[i]
+		}
+		for start := 0; start < len(all); start += opts.BatchSize {
+			end := min(start+opts.BatchSize, len(all))
+			if err := s.playerRepo.WithDB(tx).UpdateRatings(all[start:end]); err != nil {
+				return err
+			}
+		}
+
+		if err := s.standingRepo.WithDB(tx).ReplaceAll(standings, opts.BatchSize); err != nil {
+			return err
+		}
+
+		return led.recalculateRanks()
+	})
+	if err != nil {
+		s.logger.Error("ошибка пересчёта рейтингов", "error", err)
+		return nil, err
+	}
+
+	sort.Slice(report.Players, func(i, j int) bool {
+		return abs(report.Players[i].Delta) > abs(report.Players[j].Delta)
+	})
+	report.Duration = time.Since(report.StartedAt).Round(time.Millisecond).String()
+
+	s.logger.Info("пересчёт рейтингов завершён",
+		"dry_run", opts.DryRun, "matches", report.Matches, "matches_changed", report.MatchesChanged,
+		"players_changed", report.PlayersChanged, "duration", report.Duration)
+
+	return report, nil
+}
+
+// ledgerChanged сообщает, изменил ли пересчёт рейтинг или очки матча.
+func ledgerChanged(before, after *models.Match) bool {
+	return before.WinnerRatingChange != after.WinnerRatingChange ||
+		before.LoserRatingChange != after.LoserRatingChange ||
+		before.WinnerPartnerRatingChange != after.WinnerPartnerRatingChange ||
+		before.LoserPartnerRatingChange != after.LoserPartnerRatingChange ||
+		before.RatingEngine != after.RatingEngine ||
+		before.WinnerWinProbability != after.WinnerWinProbability ||
+		before.WinnerStandingPoints != after.WinnerStandingPoints ||
+		before.LoserStandingPoints != after.LoserStandingPoints ||
+		!equalRating(before.WinnerRatingBefore, after.WinnerRatingBefore) ||
+		!equalRating(before.LoserRatingBefore, after.LoserRatingBefore)
+}
+
+func equalRating(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
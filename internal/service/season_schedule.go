@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"shumnaya/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RunSchedule выполняет плановые переходы сезонов на момент now: закрывает закончившиеся,
+// при заданном шаблоне создаёт сезоны на текущий и следующий период и активирует сезон,
+// который уже начался, если активного нет. Неудавшееся действие попадает в результат
+// с текстом ошибки и повторится при следующем запуске; та же ошибка в следующий раз
+// не записывается и не пишется в лог.
+func (s *seasonService) RunSchedule(now time.Time, recurrence string) ([]models.SchedulerAction, error) {
+	var actions []models.SchedulerAction
+
+	record := func(action string, season *models.Season, err error) {
+		if !s.scheduleResultChanged(action, season.ID, err) {
+			return
+		}
+
+		a := models.SchedulerAction{At: time.Now(), Action: action, SeasonID: season.ID, SeasonName: season.Name}
+		if err != nil {
+			a.Error = err.Error()
+			if s.logger != nil {
+				s.logger.Warn("планировщик сезонов: действие не выполнено", "action", action, "season_id", season.ID, "error", err)
+			}
+		} else if s.logger != nil {
+			s.logger.Info("планировщик сезонов: действие выполнено", "action", action, "season_id", season.ID, "season", season.Name)
+		}
+		actions = append(actions, a)
+	}
+
+	ended, err := s.repo.GetEndedOpen(now)
+	if err != nil {
+		return actions, err
+	}
+	for i := range ended {
+		season := &ended[i]
+
+		// Сначала сезон перестаёт быть активным, чтобы следующий сезон можно было
+		// активировать, даже если закрыть этот пока не удаётся
+		if season.IsActive {
+			err := s.repo.Deactivate(season.ID)
+			record(models.SchedulerActionDeactivate, season, err)
+			if err != nil {
+				continue
+			}
+		}
+
+		// Матчи закончившегося сезона больше не дождутся ответа: они подтверждаются сразу,
+		// как по истечении срока автоподтверждения
+		if _, err := s.matchService.ConfirmPendingInSeason(season.ID); err != nil {
+			record(models.SchedulerActionClose, season, err)
+			continue
+		}
+
+		_, err := s.CloseSeason(season.ID)
+		record(models.SchedulerActionClose, season, err)
+	}
+
+	if recurrence != "" {
+		start, end, name := recurrencePeriod(recurrence, now)
+		nextStart, nextEnd, nextName := recurrencePeriod(recurrence, end.Add(time.Second))
+
+		for _, p := range []struct {
+			start, end time.Time
+			name       string
+		}{{start, end, name}, {nextStart, nextEnd, nextName}} {
+			exists, err := s.repo.ExistsOverlapping(p.start, p.end)
+			if err != nil {
+				return actions, err
+			}
+			if exists {
+				continue
+			}
+
+			season, err := s.seasonFromTemplate(p.name, p.start, p.end)
+			if err != nil {
+				return actions, err
+			}
+			record(models.SchedulerActionCreate, season, s.CreateSeason(season))
+		}
+	}
+
+	if _, err := s.repo.GetActive(); err == nil {
+		return actions, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return actions, err
+	}
+
+	startable, err := s.repo.GetStartable(now)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return actions, nil
+		}
+		return actions, err
+	}
+	_, err = s.ActivateSeason(startable.ID)
+	record(models.SchedulerActionActivate, startable, err)
+
+	return actions, nil
+}
+
+// scheduleResultChanged запоминает исход действия планировщика над сезоном и сообщает,
+// стоит ли его записывать: успех — всегда, ошибку — только если она не повторяет прошлую.
+func (s *seasonService) scheduleResultChanged(action string, seasonID uint, err error) bool {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+
+	key := fmt.Sprintf("%s:%d", action, seasonID)
+	if err == nil {
+		delete(s.scheduleFailures, key)
+		return true
+	}
+
+	if s.scheduleFailures[key] == err.Error() {
+		return false
+	}
+	s.scheduleFailures[key] = err.Error()
+	return true
+}
+
+// seasonFromTemplate собирает новый сезон с форматом и рейтинговыми настройками последнего сезона.
+func (s *seasonService) seasonFromTemplate(name string, start, end time.Time) (*models.Season, error) {
+	season := &models.Season{Name: name, StartDate: start, EndDate: end}
+
+	latest, err := s.repo.GetLatest()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return season, nil
+		}
+		return nil, err
+	}
+
+	season.BestOf = latest.BestOf
+	season.GamePoints = latest.GamePoints
+	season.DoublesTeamRating = latest.DoublesTeamRating
+	season.RatingEngine = latest.RatingEngine
+	season.RatingReset = latest.RatingReset
+	season.RatingRegressPercent = latest.RatingRegressPercent
+	season.Points = latest.Points
+	season.TieBreakers = latest.TieBreakers
+	return season, nil
+}
+
+// recurrencePeriod возвращает календарный месяц или квартал, в который попадает t,
+// и название сезона для него. Конец периода — последняя секунда перед следующим.
+func recurrencePeriod(recurrence string, t time.Time) (start, end time.Time, name string) {
+	switch recurrence {
+	case models.SeasonRecurrenceQuarterly:
+		quarter := (int(t.Month()) - 1) / 3
+		start = time.Date(t.Year(), time.Month(quarter*3+1), 1, 0, 0, 0, 0, t.Location())
+		end = start.AddDate(0, 3, 0).Add(-time.Second)
+		name = fmt.Sprintf("Сезон Q%d %d", quarter+1, t.Year())
+	default:
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		end = start.AddDate(0, 1, 0).Add(-time.Second)
+		name = fmt.Sprintf("Сезон %02d.%d", int(t.Month()), t.Year())
+	}
+	return start, end, name
+}
@@ -3,29 +3,76 @@ package service
 import (
 	"errors"
 	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
 	"shumnaya/internal/models"
 	"shumnaya/internal/repository"
+	"shumnaya/internal/utils/elo"
+	"shumnaya/internal/utils/rating"
+	"shumnaya/internal/utils/score"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeasonClosed            = errors.New("season is closed")
+	ErrSeasonNotActive         = errors.New("season is not active")
+	ErrSeasonEnded             = errors.New("season has already ended")
+	ErrAnotherSeasonActive     = errors.New("another season is already active")
+	ErrOutsideSeasonDates      = errors.New("match date is outside the season dates")
+	ErrSeasonHasPendingMatches = errors.New("season has matches awaiting confirmation")
+	ErrSeasonNotClosed         = errors.New("season is not closed yet")
+	ErrNotSeasonOrganizer      = errors.New("only an admin or the season organizer can manage this season")
 )
 
 type SeasonService interface {
 	CreateSeason(season *models.Season) error
-	GetAllSeasons() ([]models.Season, error)
+	GetSeasons(page models.PageRequest) (*models.SeasonPage, error)
 	GetSeasonByID(id uint) (*models.Season, error)
+	CheckSeasonAccess(id uint, actor models.Actor) error
+
+	ActivateSeason(id uint) (*models.Season, error)
+	CloseSeason(id uint) (*models.SeasonSummary, error)
+	GetSeasonResults(id uint) (*models.SeasonSummary, error)
+
+	RunSchedule(now time.Time, recurrence string) ([]models.SchedulerAction, error)
 }
 
 type seasonService struct {
-	repo   repository.SeasonRepository
-	logger *slog.Logger
+	db           *gorm.DB
+	repo         repository.SeasonRepository
+	standingRepo repository.StandingRepository
+	matchRepo    repository.MatchRepository
+	resultRepo   repository.SeasonResultRepository
+	matchService MatchService
+	logger       *slog.Logger
+
+	// Ошибки действий планировщика с прошлого запуска: повторная ошибка не записывается
+	scheduleMu       sync.Mutex
+	scheduleFailures map[string]string
 }
 
 func NewSeasonService(
+	db *gorm.DB,
 	repo repository.SeasonRepository,
+	standingRepo repository.StandingRepository,
+	matchRepo repository.MatchRepository,
+	resultRepo repository.SeasonResultRepository,
+	matchService MatchService,
 	logger *slog.Logger,
 ) SeasonService {
 	return &seasonService{
-		repo:   repo,
-		logger: logger,
+		db:           db,
+		repo:         repo,
+		standingRepo: standingRepo,
+		matchRepo:    matchRepo,
+		resultRepo:   resultRepo,
+		matchService: matchService,
+		logger:       logger,
+
+		scheduleFailures: make(map[string]string),
 	}
 }
 
@@ -43,7 +90,42 @@ func (s *seasonService) CreateSeason(season *models.Season) error {
 		return err
 	}
 
-	season.IsActive = true
+	if season.BestOf == 0 {
+		season.BestOf = score.DefaultBestOf
+	}
+	if season.GamePoints == 0 {
+		season.GamePoints = score.DefaultPointsToWin
+	}
+	if season.DoublesTeamRating == "" {
+		season.DoublesTeamRating = elo.TeamRatingAverage
+	}
+	if season.RatingEngine == "" {
+		season.RatingEngine = rating.EngineElo
+	}
+	if season.RatingReset == "" {
+		season.RatingReset = rating.ResetCarry
+	}
+	if season.Points.Win == nil {
+		win := models.DefaultWinPoints
+		season.Points.Win = &win
+	}
+	if season.Points.ForfeitWin == nil {
+		forfeitWin := models.DefaultWinPoints
+		season.Points.ForfeitWin = &forfeitWin
+	}
+	if season.RatingReset == rating.ResetRegress && season.RatingRegressPercent == 0 {
+		season.RatingRegressPercent = rating.DefaultRegressPercent
+	}
+
+	tieBreakers, err := ParseTieBreakers(season.TieBreakers)
+	if err != nil {
+		return err
+	}
+	season.TieBreakers = strings.Join(tieBreakers, ",")
+
+	// Сезон создаётся неактивным, активируется отдельно через ActivateSeason
+	season.IsActive = false
+	season.ClosedAt = nil
 
 	if err := s.repo.Create(season); err != nil {
 		if s.logger != nil {
@@ -58,8 +140,8 @@ func (s *seasonService) CreateSeason(season *models.Season) error {
 	return nil
 }
 
-func (s *seasonService) GetAllSeasons() ([]models.Season, error) {
-	seasons, err := s.repo.GetAll()
+func (s *seasonService) GetSeasons(page models.PageRequest) (*models.SeasonPage, error) {
+	seasons, err := s.repo.GetPage(page)
 	if err != nil {
 		if s.logger != nil {
 			s.logger.Error(
@@ -86,3 +168,224 @@ func (s *seasonService) GetSeasonByID(id uint) (*models.Season, error) {
 	}
 	return season, nil
 }
+
+// CheckSeasonAccess проверяет, что actor может активировать и закрывать сезон:
+// администратор — любой, организатор — только свой. Сами ActivateSeason и CloseSeason
+// прав не проверяют, их вызывает и планировщик.
+func (s *seasonService) CheckSeasonAccess(id uint, actor models.Actor) error {
+	season, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if !actor.CanManageSeason(season) {
+		return ErrNotSeasonOrganizer
+	}
+	return nil
+}
+
+// ActivateSeason делает сезон активным. Активным может быть только один сезон;
+// закрытый или уже закончившийся сезон активировать нельзя.
+func (s *seasonService) ActivateSeason(id uint) (*models.Season, error) {
+	var activated *models.Season
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		repoTx := s.repo.WithDB(tx)
+
+		season, err := repoTx.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if season.IsClosed() {
+			return ErrSeasonClosed
+		}
+		if time.Now().After(season.EndDate) {
+			return ErrSeasonEnded
+		}
+
+		if season.IsActive {
+			activated = season
+			return nil
+		}
+
+		active, err := repoTx.GetActive()
+		if err == nil && active.ID != season.ID {
+			return ErrAnotherSeasonActive
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		season.IsActive = true
+		if err := repoTx.Update(season); err != nil {
+			return err
+		}
+
+		activated = season
+		return nil
+	})
+
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("service: ошибка активации сезона", "season_id", id, "error", err)
+		}
+		return nil, err
+	}
+
+	return activated, nil
+}
+
+// CloseSeason закрывает сезон: проставляет места в таблицах, сохраняет неизменяемый
+// снимок итоговых таблиц и выдаёт награды. После закрытия матчи сезона нельзя
+// записывать, подтверждать, исправлять и удалять.
+func (s *seasonService) CloseSeason(id uint) (*models.SeasonSummary, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		season, err := s.repo.WithDB(tx).GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if season.IsClosed() {
+			return ErrSeasonClosed
+		}
+
+		pending, err := s.matchRepo.WithDB(tx).CountBySeasonAndStatus(id, models.MatchStatusPending)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return ErrSeasonHasPendingMatches
+		}
+
+		tieBreakers, err := ParseTieBreakers(season.TieBreakers)
+		if err != nil {
+			return err
+		}
+
+		var results []models.SeasonResult
+		var awards []models.SeasonAward
+
+		for _, matchType := range []string{models.MatchTypeSingles, models.MatchTypeDoubles} {
+			if err := s.standingRepo.WithDB(tx).RecalculateRanks(id, matchType, tieBreakers, rankWeek(time.Now())); err != nil {
+				return err
+			}
+
+			standings, err := s.standingRepo.WithDB(tx).GetSeasonStandingsOrdered(id, matchType, models.StandingsOrderPoints)
+			if err != nil {
+				return err
+			}
+			standings = playedStandings(standings)
+
+			for _, st := range standings {
+				results = append(results, models.SeasonResult{
+					SeasonID: id,
+					Type:     matchType,
+					PlayerID: st.PlayerID,
+					Rank:     st.Rank,
+					Wins:     st.Wins,
+					Losses:   st.Losses,
+					Points:   st.Points,
+					Rating:   st.Rating,
+				})
+			}
+			awards = append(awards, seasonAwards(id, matchType, standings)...)
+		}
+
+		if err := s.resultRepo.WithDB(tx).CreateResults(results); err != nil {
+			return err
+		}
+		if err := s.resultRepo.WithDB(tx).CreateAwards(awards); err != nil {
+			return err
+		}
+
+		return s.repo.WithDB(tx).CloseSeason(id)
+	})
+
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("service: ошибка закрытия сезона", "season_id", id, "error", err)
+		}
+		return nil, err
+	}
+
+	if s.logger != nil {
+		s.logger.Info("service: сезон закрыт, итоги зафиксированы", "season_id", id)
+	}
+
+	return s.GetSeasonResults(id)
+}
+
+// GetSeasonResults возвращает зафиксированные итоги закрытого сезона.
+func (s *seasonService) GetSeasonResults(id uint) (*models.SeasonSummary, error) {
+	season, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !season.IsClosed() {
+		return nil, ErrSeasonNotClosed
+	}
+
+	results, err := s.resultRepo.GetResults(id)
+	if err != nil {
+		return nil, err
+	}
+	awards, err := s.resultRepo.GetAwards(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SeasonSummary{
+		SeasonID: id,
+		ClosedAt: season.ClosedAt,
+		Results:  results,
+		Awards:   awards,
+	}, nil
+}
+
+// playedStandings отбрасывает строки игроков без единого матча в сезоне.
+func playedStandings(standings []models.Standing) []models.Standing {
+	played := standings[:0]
+	for _, st := range standings {
+		if st.Wins+st.Losses > 0 {
+			played = append(played, st)
+		}
+	}
+	return played
+}
+
+// seasonAwards выдаёт награды разряда: призовые места и лучших по победам, матчам и
+// сезонному рейтингу. При равенстве награду получают все лидеры.
+func seasonAwards(seasonID uint, matchType string, standings []models.Standing) []models.SeasonAward {
+	var awards []models.SeasonAward
+	if len(standings) == 0 {
+		return awards
+	}
+
+	podium := map[int]string{1: models.AwardChampion, 2: models.AwardRunnerUp, 3: models.AwardThirdPlace}
+	for _, st := range standings {
+		if award, ok := podium[st.Rank]; ok {
+			awards = append(awards, models.SeasonAward{SeasonID: seasonID, Type: matchType, Award: award, PlayerID: st.PlayerID, Value: st.Rank})
+		}
+	}
+
+	leaders := []struct {
+		award string
+		value func(st *models.Standing) int
+	}{
+		{models.AwardMostWins, func(st *models.Standing) int { return st.Wins }},
+		{models.AwardMostMatches, func(st *models.Standing) int { return st.Wins + st.Losses }},
+		{models.AwardTopRated, func(st *models.Standing) int { return st.Rating }},
+	}
+
+	for _, l := range leaders {
+		best := l.value(&standings[0])
+		for i := range standings {
+			best = max(best, l.value(&standings[i]))
+		}
+		for i := range standings {
+			if l.value(&standings[i]) == best {
+				awards = append(awards, models.SeasonAward{SeasonID: seasonID, Type: matchType, Award: l.award, PlayerID: standings[i].PlayerID, Value: best})
+			}
+		}
+	}
+
+	return awards
+}
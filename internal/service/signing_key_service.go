@@ -0,0 +1,143 @@
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"shumnaya/internal/config"
+	"shumnaya/internal/models"
+	"shumnaya/internal/repository"
+	"shumnaya/internal/utils/jwtkeys"
+
+	"gorm.io/gorm"
+)
+
+// SigningKeyService ведёт ключи подписи access-токенов: заранее публикует следующий ключ,
+// переводит на него подпись, выводит старый с льготным периодом и загружает действующие
+// ключи в jwtkeys.Manager.
+type SigningKeyService interface {
+	// Sync выпускает следующий ключ, если подошёл срок ротации или сменился алгоритм,
+	// выводит ключи, сменённые вступившим в силу следующим, и перечитывает ключи
+	// (в том числе выпущенные другими экземплярами сервиса).
+	Sync() error
+	// Rotate немедленно выпускает следующий ключ (например, при подозрении на утечку)
+	// и возвращает его kid. Подписывать он начнёт через JWTConfig.PublishDelay.
+	Rotate() (string, error)
+}
+
+type signingKeyService struct {
+	db      *gorm.DB
+	logger  *slog.Logger
+	cfg     config.JWTConfig
+	manager *jwtkeys.Manager
+	keyRepo repository.SigningKeyRepository
+}
+
+func NewSigningKeyService(db *gorm.DB, log *slog.Logger, cfg config.JWTConfig, manager *jwtkeys.Manager, kr repository.SigningKeyRepository) SigningKeyService {
+	return &signingKeyService{db: db, logger: log, cfg: cfg, manager: manager, keyRepo: kr}
+}
+
+func (s *signingKeyService) Sync() error {
+	_, err := s.rotate(false)
+	return err
+}
+
+func (s *signingKeyService) Rotate() (string, error) {
+	return s.rotate(true)
+}
+
+// rotate возвращает kid следующего ключа, а если его нет — подписывающего.
+func (s *signingKeyService) rotate(force bool) (string, error) {
+	now := time.Now()
+	var keys []models.SigningKey
+	var kid string
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		keyRepoTx := s.keyRepo.WithDB(tx)
+
+		if err := keyRepoTx.LockRotation(); err != nil {
+			return err
+		}
+
+		usable, err := keyRepoTx.GetUsable(now)
+		if err != nil {
+			return err
+		}
+
+		// active подписывает токены, next опубликован и начнёт подписывать в ActivatesAt
+		var active, next *models.SigningKey
+		for i := range usable {
+			key := &usable[i]
+			switch {
+			case key.RetiredAt != nil:
+			case key.ActivatesAt.After(now):
+				next = key
+			case active == nil || key.ActivatesAt.After(active.ActivatesAt):
+				active = key
+			}
+		}
+
+		// Ключи, которые сменил вступивший в силу active, перестали подписывать в момент его активации
+		if active != nil {
+			for _, key := range usable {
+				if key.RetiredAt != nil || key.KID == active.KID || key.ActivatesAt.After(now) {
+					continue
+				}
+				if err := keyRepoTx.Retire(key.KID, active.ActivatesAt, active.ActivatesAt.Add(s.cfg.GracePeriod)); err != nil {
+					return err
+				}
+				s.logger.Info("service: ключ подписи выведен из оборота", "kid", key.KID, "replaced_by", active.KID)
+			}
+		}
+
+		due := active == nil || next == nil && (force ||
+			active.Algorithm != s.cfg.Algorithm ||
+			now.Sub(active.ActivatesAt) >= s.cfg.RotationInterval-s.cfg.PublishDelay)
+
+		if due {
+			key, err := jwtkeys.GenerateKey(s.cfg.Algorithm, now)
+			if err != nil {
+				return err
+			}
+			// Первый ключ подписывает сразу: токенов, которые другие экземпляры не смогли бы проверить, ещё нет
+			key.ActivatesAt = now.Add(s.cfg.PublishDelay)
+			if active == nil {
+				key.ActivatesAt = now
+			}
+			if err := keyRepoTx.Create(key); err != nil {
+				return err
+			}
+
+			if active == nil {
+				active = key
+			} else {
+				next = key
+			}
+
+			s.logger.Info("service: выпущен новый ключ подписи токенов",
+				"kid", key.KID, "algorithm", key.Algorithm, "activates_at", key.ActivatesAt, "forced", force)
+		}
+
+		kid = active.KID
+		if next != nil {
+			kid = next.KID
+		}
+
+		if removed, err := keyRepoTx.DeleteExpired(now); err != nil {
+			return err
+		} else if removed > 0 {
+			s.logger.Info("service: удалены истёкшие ключи подписи", "count", removed)
+		}
+
+		keys, err = keyRepoTx.GetUsable(now)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.manager.SetKeys(keys); err != nil {
+		return "", err
+	}
+	return kid, nil
+}
@@ -3,12 +3,14 @@ package service
 import (
 	"fmt"
 	"log/slog"
+	"time"
+
 	"shumnaya/internal/models"
 	"shumnaya/internal/repository"
 )
 
 type StandingService interface {
-	GetSeasonStandings(seasonID uint) ([]models.Standing, error)
+	GetSeasonStandings(seasonID uint, matchType, orderBy string, page models.PageRequest) (*models.StandingPage, error)
 }
 
 type standingService struct {
@@ -20,9 +22,9 @@ func NewStandingService(repo repository.StandingRepository, log *slog.Logger) St
 	return &standingService{repo: repo, log: log}
 }
 
-func (s *standingService) GetSeasonStandings(seasonID uint) ([]models.Standing, error) {
+func (s *standingService) GetSeasonStandings(seasonID uint, matchType, orderBy string, page models.PageRequest) (*models.StandingPage, error) {
 
-	standings, err := s.repo.GetSeasonStandingsOrdered(seasonID)
+	result, err := s.repo.GetSeasonStandingsPage(seasonID, matchType, orderBy, page)
 
 	if err != nil {
 		s.log.Error(
@@ -31,6 +33,23 @@ func (s *standingService) GetSeasonStandings(seasonID uint) ([]models.Standing,
 			"error", err)
 		return nil, fmt.Errorf("Ошибка при получении Standings: %w", err)
 	}
+	standings := result.Data
+
+	previous, err := s.repo.GetPreviousRanks(seasonID, matchType, rankWeek(time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("Ошибка при получении мест прошлой недели: %w", err)
+	}
+
+	for i := range standings {
+		rank, ok := previous[standings[i].PlayerID]
+		if !ok {
+			continue
+		}
+		standings[i].PreviousRank = &rank
+		if standings[i].Rank > 0 && rank > 0 {
+			standings[i].Movement = rank - standings[i].Rank
+		}
+	}
 
 	s.log.Info(
 		"service: турнирная таблица успешно получена",
@@ -38,6 +57,6 @@ func (s *standingService) GetSeasonStandings(seasonID uint) ([]models.Standing,
 		"count", len(standings),
 	)
 
-	return standings, nil
+	return result, nil
 
 }
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/repository"
+	"shumnaya/internal/utils/elo"
+)
+
+const (
+	formMatches    = 10 // сколько последних матчей входит в форму
+	opponentsLimit = 3  // сколько любимых соперников и «неудобных» соперников показывать
+
+	DefaultHeadToHeadLimit = 5
+	MaxHeadToHeadLimit     = 50
+)
+
+var ErrSamePlayer = errors.New("player and opponent must be different")
+
+type StatsService interface {
+	GetPlayerStats(playerID uint, matchType string) (*models.PlayerStats, error)
+	GetHeadToHead(playerAID, playerBID uint, matchType string, limit int) (*models.HeadToHeadRecord, error)
+}
+
+type statsService struct {
+	logger     *slog.Logger
+	playerRepo repository.PlayerRepository
+	matchRepo  repository.MatchRepository
+	statsRepo  repository.StatsRepository
+}
+
+func NewStatsService(log *slog.Logger, pr repository.PlayerRepository, mr repository.MatchRepository, sr repository.StatsRepository) StatsService {
+	return &statsService{logger: log, playerRepo: pr, matchRepo: mr, statsRepo: sr}
+}
+
+// GetPlayerStats собирает статистику игрока по подтверждённым матчам: общий счёт,
+// серии, форму, результаты против сильных и слабых соперников, разбивку по сезонам
+// и любимых и неудобных соперников. matchType пустой — матчи любого разряда.
+func (s *statsService) GetPlayerStats(playerID uint, matchType string) (*models.PlayerStats, error) {
+	if _, err := s.playerRepo.GetByID(playerID); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.statsRepo.GetSummary(playerID, matchType)
+	if err != nil {
+		return nil, err
+	}
+
+	streaks, err := s.statsRepo.GetStreaks(playerID, matchType)
+	if err != nil {
+		return nil, err
+	}
+	stats.Streaks = *streaks
+
+	recent, err := s.statsRepo.GetRecentResults(playerID, matchType, formMatches)
+	if err != nil {
+		return nil, err
+	}
+	stats.Form = playerForm(recent)
+
+	if stats.Seasons, err = s.statsRepo.GetSeasonSplits(playerID, matchType); err != nil {
+		return nil, err
+	}
+	if stats.Favourites, err = s.statsRepo.GetOpponents(playerID, matchType, false, opponentsLimit); err != nil {
+		return nil, err
+	}
+	if stats.Nemeses, err = s.statsRepo.GetOpponents(playerID, matchType, true, opponentsLimit); err != nil {
+		return nil, err
+	}
+
+	summary := &stats.Summary
+	summary.WinRate = ratio(summary.Wins, summary.Matches)
+	summary.GamesWonRatio = ratio(summary.GamesWon, summary.GamesWon+summary.GamesLost)
+	summary.PointsWonRatio = ratio(summary.PointsWon, summary.PointsWon+summary.PointsLost)
+
+	stats.VsHigherRated.WinRate = ratio(stats.VsHigherRated.Wins, stats.VsHigherRated.Matches)
+	stats.VsLowerRated.WinRate = ratio(stats.VsLowerRated.Wins, stats.VsLowerRated.Matches)
+	for i := range stats.Seasons {
+		stats.Seasons[i].WinRate = ratio(stats.Seasons[i].Wins, stats.Seasons[i].Matches)
+	}
+	for i := range stats.Favourites {
+		stats.Favourites[i].WinRate = ratio(stats.Favourites[i].Wins, stats.Favourites[i].Matches)
+	}
+	for i := range stats.Nemeses {
+		stats.Nemeses[i].WinRate = ratio(stats.Nemeses[i].Wins, stats.Nemeses[i].Matches)
+	}
+
+	s.logger.Info("service: статистика игрока собрана", "player_id", playerID, "type", matchType, "matches", summary.Matches)
+
+	return stats, nil
+}
+
+// GetHeadToHead собирает историю встреч игроков A и B разряда matchType: общий счёт,
+// партии, очки, изменения рейтинга, серии, разбивку по сезонам, limit последних встреч
+// (по умолчанию DefaultHeadToHeadLimit, не больше MaxHeadToHeadLimit) и прогноз следующей встречи.
+func (s *statsService) GetHeadToHead(playerAID, playerBID uint, matchType string, limit int) (*models.HeadToHeadRecord, error) {
+	if playerAID == playerBID {
+		return nil, ErrSamePlayer
+	}
+	if limit <= 0 {
+		limit = DefaultHeadToHeadLimit
+	}
+	limit = min(limit, MaxHeadToHeadLimit)
+
+	playerA, err := s.playerRepo.GetByID(playerAID)
+	if err != nil {
+		return nil, err
+	}
+	playerB, err := s.playerRepo.GetByID(playerBID)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.statsRepo.GetHeadToHead(playerAID, playerBID, matchType)
+	if err != nil {
+		return nil, err
+	}
+
+	streaks, err := s.statsRepo.GetHeadToHeadStreaks(playerAID, playerBID, matchType)
+	if err != nil {
+		return nil, err
+	}
+	record.Streaks = *streaks
+
+	if record.Seasons, err = s.statsRepo.GetHeadToHeadSeasons(playerAID, playerBID, matchType); err != nil {
+		return nil, err
+	}
+	if record.LastMatchesPlayed, err = s.matchRepo.HeadToHeadRecentMatches(playerAID, playerBID, matchType, limit); err != nil {
+		return nil, err
+	}
+
+	probability := elo.ExpectedScore(playerA.Rating, playerB.Rating)
+	record.Prediction = models.HeadToHeadPrediction{
+		PlayerARating:         playerA.Rating,
+		PlayerBRating:         playerB.Rating,
+		PlayerAWinProbability: math.Round(probability*1000) / 1000,
+		PlayerBWinProbability: math.Round((1-probability)*1000) / 1000,
+	}
+
+	return record, nil
+}
+
+// playerForm переводит исходы последних матчей (новые первыми) в строку формы.
+func playerForm(recent []bool) models.PlayerForm {
+	var form models.PlayerForm
+	var b strings.Builder
+	for _, won := range recent {
+		if won {
+			b.WriteByte('W')
+			form.Wins++
+		} else {
+			b.WriteByte('L')
+			form.Losses++
+		}
+	}
+	form.Results = b.String()
+	return form
+}
+
+// ratio — доля part от total с точностью до тысячных, 0 при пустом total.
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*1000) / 1000
+}
@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"shumnaya/internal/dto"
+	"shumnaya/internal/ratelimit"
+	"shumnaya/internal/service"
+	"shumnaya/internal/transport/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	service service.AccountService
+	logger  *slog.Logger
+}
+
+func NewAccountHandler(r *gin.Engine, svc service.AccountService, logger *slog.Logger) *AccountHandler {
+	return &AccountHandler{service: svc, logger: logger}
+}
+
+func (h *AccountHandler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/auth/verify-email", h.VerifyEmail)
+	r.POST("/auth/password-reset", h.ResetPassword)
+}
+
+// RequestEmailVerification godoc
+// @Summary Повторно отправить письмо подтверждения email
+// @Description Отправляет на email игрока новую ссылку подтверждения. Без подтверждения нельзя записывать матчи.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 202
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string "Слишком много писем; см. Retry-After"
+// @Failure 500 {object} map[string]string
+// @Router /auth/verify-email/request [post]
+func (h *AccountHandler) RequestEmailVerification(c *gin.Context) {
+	if err := h.service.RequestEmailVerification(c.GetUint("player_id")); err != nil {
+		h.writeAccountError(c, err, "failed to send verification email")
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// VerifyEmail godoc
+// @Summary Подтвердить email
+// @Description Принимает токен из ссылки в письме. Ссылка одноразовая.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.VerifyEmailRequest true "Токен из письма"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/verify-email [post]
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.service.VerifyEmail(req.Token); err != nil {
+		h.writeAccountError(c, err, "failed to verify email")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestPasswordReset godoc
+// @Summary Запросить сброс пароля
+// @Description Отправляет ссылку сброса пароля, если игрок с таким email зарегистрирован.
+// @Description Ответ не зависит от того, есть ли такой игрок.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.PasswordResetRequest true "Email"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string "Слишком много писем; см. Retry-After"
+// @Failure 500 {object} map[string]string
+// @Router /auth/password-reset/request [post]
+func (h *AccountHandler) RequestPasswordReset(c *gin.Context) {
+	var req dto.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(req.Email); err != nil {
+		h.writeAccountError(c, err, "failed to send password reset email")
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+// @Summary Задать новый пароль
+// @Description Принимает токен из письма и новый пароль. Ссылка одноразовая; все сессии игрока завершаются.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.PasswordResetConfirmRequest true "Токен из письма и новый пароль"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/password-reset [post]
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req dto.PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.service.ResetPassword(req.Token, req.Password); err != nil {
+		h.writeAccountError(c, err, "failed to reset password")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeAccountError переводит ошибки сервиса учётных записей в HTTP-ответы.
+func (h *AccountHandler) writeAccountError(c *gin.Context, err error, message string) {
+	var limitErr *ratelimit.LimitError
+	switch {
+	case errors.As(err, &limitErr):
+		middleware.RejectLimited(c, limitErr)
+	case errors.Is(err, service.ErrInvalidAccountToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmailAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(message, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
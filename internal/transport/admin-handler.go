@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeasonScheduler — состояние фонового планировщика сезонов (worker.SeasonScheduler).
+type SeasonScheduler interface {
+	Status() models.SchedulerStatus
+}
+
+type AdminHandler struct {
+	recomputeService service.RecomputeService
+	scheduler        SeasonScheduler
+	logger           *slog.Logger
+}
+
+func NewAdminHandler(r *gin.Engine, recomputeService service.RecomputeService, scheduler SeasonScheduler, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{recomputeService: recomputeService, scheduler: scheduler, logger: logger}
+}
+
+// SchedulerStatus godoc
+// @Summary Журнал планировщика сезонов
+// @Description Настройки планировщика и его последние действия (закрытие, активация и создание сезонов), новые первыми
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SchedulerStatus
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/season-scheduler [get]
+func (h *AdminHandler) SchedulerStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.scheduler.Status())
+}
+
+// Recompute godoc
+// @Summary Пересчитать рейтинги по всей истории
+// @Description Заново проводит все подтверждённые матчи по текущим правилам рейтинга: пересобирает рейтинги игроков, изменения рейтинга в матчах, историю рейтинга и турнирные таблицы. В режиме dry_run ничего не сохраняет и только возвращает разницу по игрокам.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param dry_run query bool false "Только посчитать разницу, ничего не сохраняя"
+// @Param batch_size query int false "Размер пачки матчей (по умолчанию 5000, максимум 10000)"
+// @Param top query int false "Сколько игроков с наибольшим изменением вернуть (по умолчанию 100, 0 — всех)"
+// @Success 200 {object} models.RecomputeReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/recompute [post]
+func (h *AdminHandler) Recompute(c *gin.Context) {
+	opts := service.RecomputeOptions{}
+
+	if dryRunStr := c.Query("dry_run"); dryRunStr != "" {
+		dryRun, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run format"})
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	if batchSizeStr := c.Query("batch_size"); batchSizeStr != "" {
+		batchSize, err := strconv.Atoi(batchSizeStr)
+		if err != nil || batchSize <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch_size"})
+			return
+		}
+		opts.BatchSize = batchSize
+	}
+
+	top := 100
+	if topStr := c.Query("top"); topStr != "" {
+		var err error
+		top, err = strconv.Atoi(topStr)
+		if err != nil || top < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid top"})
+			return
+		}
+	}
+
+	report, err := h.recomputeService.Recompute(opts)
+	if err != nil {
+		h.logger.Error("failed to recompute ratings", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to recompute ratings"})
+		return
+	}
+
+	if top > 0 && len(report.Players) > top {
+		report.Players = report.Players[:top]
+	}
+
+	c.JSON(http.StatusOK, report)
+}
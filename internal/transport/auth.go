@@ -1,4 +1,112 @@
 package transport
 
-// Заглушка для будущих Auth-эндпоинтов. Оставлено намеренно пустым,
-// чтобы не нарушать сборку проекта и генерацию Swagger.
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"shumnaya/internal/dto"
+	"shumnaya/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	service service.AuthService
+	logger  *slog.Logger
+}
+
+func NewAuthHandler(r *gin.Engine, svc service.AuthService, logger *slog.Logger) *AuthHandler {
+	return &AuthHandler{service: svc, logger: logger}
+}
+
+func (h *AuthHandler) RegisterRoutes(r *gin.Engine) {
+	r.POST("/auth/refresh", h.Refresh)
+	r.POST("/auth/logout", h.Logout)
+}
+
+// Refresh godoc
+// @Summary Обновить токены
+// @Description Обменивает refresh-токен на новую пару токенов. Каждый refresh-токен действует один раз;
+// @Description повторное использование уже обменянного токена отзывает всю сессию.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.RefreshRequest true "Refresh-токен"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	tokens, err := h.service.Refresh(req.RefreshToken)
+	if err != nil {
+		h.writeAuthError(c, err, "failed to refresh tokens")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewLoginResponse(tokens))
+}
+
+// Logout godoc
+// @Summary Выйти
+// @Description Завершает сессию, к которой относится refresh-токен. Выданный access-токен действует до истечения своего срока.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.RefreshRequest true "Refresh-токен"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.service.Logout(req.RefreshToken); err != nil {
+		h.writeAuthError(c, err, "failed to log out")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary Выйти на всех устройствах
+// @Description Завершает все сессии игрока. Выданные access-токены действуют до истечения своего срока.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if err := h.service.LogoutAll(c.GetUint("player_id")); err != nil {
+		h.writeAuthError(c, err, "failed to log out")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeAuthError переводит ошибки сервиса токенов в HTTP-ответы.
+func (h *AuthHandler) writeAuthError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRefreshToken), errors.Is(err, service.ErrRefreshTokenReused):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(message, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"shumnaya/internal/service"
+	"shumnaya/internal/utils/jwtkeys"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KeysHandler struct {
+	keys    *jwtkeys.Manager
+	service service.SigningKeyService
+	logger  *slog.Logger
+}
+
+func NewKeysHandler(r *gin.Engine, keys *jwtkeys.Manager, svc service.SigningKeyService, logger *slog.Logger) *KeysHandler {
+	return &KeysHandler{keys: keys, service: svc, logger: logger}
+}
+
+func (h *KeysHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/.well-known/jwks.json", h.JWKS)
+}
+
+// JWKS godoc
+// @Summary Открытые ключи подписи токенов
+// @Description Ключи (JWK Set, RFC 7517) для проверки access-токенов другими сервисами: текущий ключ подписи,
+// @Description следующий ключ (публикуется заранее, до начала подписи) и выведенные ключи, токены которых ещё действуют. Ключ выбирается по kid из заголовка токена;
+// @Description при незнакомом kid набор нужно перезапросить — ключ мог смениться после ротации.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} jwtkeys.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *KeysHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(jwtkeys.JWKSCacheMaxAge.Seconds())))
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
+
+// RotateKeys godoc
+// @Summary Ротировать ключ подписи токенов
+// @Description Немедленно выпускает новый ключ подписи и публикует его в JWKS; подписывать он начинает
+// @Description через JWT_KEY_PUBLISH_DELAY. После этого прежний ключ перестаёт подписывать,
+// @Description но проверяет уже выданные токены до конца льготного периода. Если следующий ключ уже
+// @Description выпущен и ждёт начала подписи, возвращается он.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "kid нового ключа"
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/jwt/rotate [post]
+func (h *KeysHandler) RotateKeys(c *gin.Context) {
+	kid, err := h.service.Rotate()
+	if err != nil {
+		h.logger.Error("ошибка ротации ключа подписи", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to rotate signing key"})
+		return
+	}
+
+	h.logger.Info("ключ подписи ротирован вручную", "kid", kid, "admin_id", c.GetUint("player_id"))
+	c.JSON(http.StatusOK, gin.H{"kid": kid})
+}
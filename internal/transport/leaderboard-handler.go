@@ -0,0 +1,104 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/service"
+	"shumnaya/internal/utils/cursor"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaderboardHandler struct {
+	service service.LeaderboardService
+	logger  *slog.Logger
+}
+
+func NewLeaderboardHandler(r *gin.Engine, svc service.LeaderboardService, logger *slog.Logger) *LeaderboardHandler {
+	return &LeaderboardHandler{service: svc, logger: logger}
+}
+
+// GetLeaderboard godoc
+// @Summary Рейтинг-лист игроков
+// @Description Игроки по убыванию рейтинга; игроки с равным рейтингом делят место. Без season_id — общий рейтинг,
+// @Description с season_id — сезонный рейтинг разряда type (по умолчанию одиночного) среди сыгравших в сезоне.
+// @Description Игроки с числом матчей меньше 10 помечаются как provisional. Если передан токен, строка игрока
+// @Description помечается is_me, а его место при тех же фильтрах возвращается в me.
+// @Tags Players
+// @Produce json
+// @Param season_id query int false "ID сезона"
+// @Param type query string false "Разряд матчей" Enums(singles, doubles)
+// @Param min_games query int false "Не меньше стольких подтверждённых матчей"
+// @Param active_days query int false "Последний матч не раньше стольких дней назад"
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param with_total query bool false "Посчитать общее число игроков по фильтру"
+// @Success 200 {object} models.LeaderboardPage
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /leaderboard [get]
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	var filter models.LeaderboardFilter
+
+	if seasonIDStr := c.Query("season_id"); seasonIDStr != "" {
+		seasonID, err := strconv.ParseUint(seasonIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season_id format"})
+			return
+		}
+		seasonIDUint := uint(seasonID)
+		filter.SeasonID = &seasonIDUint
+	}
+
+	if matchType := c.Query("type"); matchType != "" {
+		if matchType != models.MatchTypeSingles && matchType != models.MatchTypeDoubles {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, expected singles or doubles"})
+			return
+		}
+		filter.Type = &matchType
+	}
+
+	if minGamesStr := c.Query("min_games"); minGamesStr != "" {
+		minGames, err := strconv.Atoi(minGamesStr)
+		if err != nil || minGames < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_games"})
+			return
+		}
+		filter.MinGames = minGames
+	}
+
+	if activeDaysStr := c.Query("active_days"); activeDaysStr != "" {
+		activeDays, err := strconv.Atoi(activeDaysStr)
+		if err != nil || activeDays <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid active_days"})
+			return
+		}
+		since := time.Now().AddDate(0, 0, -activeDays)
+		filter.ActiveSince = &since
+	}
+
+	page, err := parsePageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	leaderboard, err := h.service.GetLeaderboard(filter, page, c.GetUint("player_id"))
+	if err != nil {
+		if errors.Is(err, cursor.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to get leaderboard", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get leaderboard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+}
@@ -1,15 +1,19 @@
 package transport
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
 
 	"shumnaya/internal/models"
 	"shumnaya/internal/service"
+	"shumnaya/internal/utils/cursor"
+	"shumnaya/internal/utils/score"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type MatchHandler struct {
@@ -23,21 +27,33 @@ func NewMatchHandler(r *gin.Engine, service service.MatchService, logger *slog.L
 
 func (h *MatchHandler) RegisterRoutes(r *gin.Engine) {
 	r.GET("/matches", h.GetMatches)
-	r.POST("/matches", h.CreateMatch)
-	r.GET("/players/:id/vs/:opponentId/:limit", h.GetHeadToHead)
 }
 
 // GetMatches godoc
 // @Summary Список матчей
-// @Description Получить список матчей с фильтрами
+// @Description Получить страницу матчей с фильтрами. Матчи упорядочены по (played_at, id);
+// @Description следующая страница запрашивается с cursor из next_cursor предыдущей и той же сортировкой.
 // @Tags Matches
 // @Accept json
 // @Produce json
 // @Param season_id query int false "ID сезона"
 // @Param player_id query int false "ID игрока"
-// @Param from query string false "Дата начала (ДД.ММ.ГГ)" example(25.12.24)
-// @Param to query string false "Дата конца (ДД.ММ.ГГ)" example(31.12.24)
-// @Success 200 {object} map[string]interface{}
+// @Param status query string false "Статус матча" Enums(pending, confirmed, disputed)
+// @Param type query string false "Разряд матча" Enums(singles, doubles)
+// @Param opponent_id query int false "ID соперника игрока player_id (с любой стороны пары)"
+// @Param result query string false "Исход для игрока player_id" Enums(won, lost)
+// @Param score query string false "Счёт по партиям, победитель первым, например 3:0" example(3:0)
+// @Param min_rating_diff query int false "Минимальная разница рейтингов победителя и проигравшего перед матчем"
+// @Param max_rating_diff query int false "Максимальная разница рейтингов победителя и проигравшего перед матчем"
+// @Param upsets query bool false "Только победы более слабой по рейтингу стороны"
+// @Param venue query string false "Место проведения (без учёта регистра)"
+// @Param from query string false "Дата начала: RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ" example(2024-12-25)
+// @Param to query string false "Дата конца: RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ (дата без времени — включая весь день)" example(2024-12-31)
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param sort query string false "Сортировка: -played_at (по умолчанию, новые первыми) или played_at" Enums(-played_at, played_at)
+// @Param with_total query bool false "Посчитать общее число матчей по фильтру"
+// @Success 200 {object} models.MatchPage
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /matches [get]
@@ -57,6 +73,24 @@ func (h *MatchHandler) GetMatches(c *gin.Context) {
 		}
 	}
 
+	if status := c.Query("status"); status != "" {
+		switch status {
+		case models.MatchStatusPending, models.MatchStatusConfirmed, models.MatchStatusDisputed:
+			filter.Status = &status
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status, expected pending, confirmed or disputed"})
+			return
+		}
+	}
+
+	if matchType := c.Query("type"); matchType != "" {
+		if matchType != models.MatchTypeSingles && matchType != models.MatchTypeDoubles {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, expected singles or doubles"})
+			return
+		}
+		filter.Type = &matchType
+	}
+
 	if playerIDStr := c.Query("player_id"); playerIDStr != "" {
 		if playerID, err := strconv.ParseUint(playerIDStr, 10, 32); err == nil {
 			playerIDUint := uint(playerID)
@@ -69,51 +103,129 @@ func (h *MatchHandler) GetMatches(c *gin.Context) {
 		}
 	}
 
+	if opponentIDStr := c.Query("opponent_id"); opponentIDStr != "" {
+		opponentID, err := strconv.ParseUint(opponentIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opponent_id format"})
+			return
+		}
+		opponentIDUint := uint(opponentID)
+		filter.OpponentID = &opponentIDUint
+	}
+
+	if result := c.Query("result"); result != "" {
+		if result != models.MatchResultWon && result != models.MatchResultLost {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid result, expected won or lost"})
+			return
+		}
+		filter.Result = &result
+	}
+
+	if (filter.OpponentID != nil || filter.Result != nil) && filter.PlayerID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "opponent_id and result require player_id"})
+		return
+	}
+
+	if scoreStr := c.Query("score"); scoreStr != "" {
+		winnerGames, loserGames, err := parseGamesScore(scoreStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.WinnerGames = &winnerGames
+		filter.LoserGames = &loserGames
+	}
+
+	if minDiffStr := c.Query("min_rating_diff"); minDiffStr != "" {
+		minDiff, err := strconv.Atoi(minDiffStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_rating_diff format"})
+			return
+		}
+		filter.MinRatingDiff = &minDiff
+	}
+
+	if maxDiffStr := c.Query("max_rating_diff"); maxDiffStr != "" {
+		maxDiff, err := strconv.Atoi(maxDiffStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid max_rating_diff format"})
+			return
+		}
+		filter.MaxRatingDiff = &maxDiff
+	}
+
+	if upsetsStr := c.Query("upsets"); upsetsStr != "" {
+		upsets, err := strconv.ParseBool(upsetsStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid upsets format"})
+			return
+		}
+		filter.UpsetsOnly = upsets
+	}
+
+	if venue := strings.TrimSpace(c.Query("venue")); venue != "" {
+		filter.Venue = &venue
+	}
+
 	if fromStr := c.Query("from"); fromStr != "" {
-		if fromTime, err := time.Parse("02.01.06", fromStr); err == nil {
-			filter.FromDate = &fromTime
-			h.logger.Info("фильтр по начальной дате", "from", fromTime)
-		} else {
+		fromTime, err := parseDateBound(fromStr, false)
+		if err != nil {
 			h.logger.Warn("некорректный параметр from", "значение", fromStr, "ошибка", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный формат даты from, используйте ДД.ММ.ГГ (например: 25.12.24)"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
 			return
 		}
+		filter.FromDate = &fromTime
+		h.logger.Info("фильтр по начальной дате", "from", fromTime)
 	}
 
 	if toStr := c.Query("to"); toStr != "" {
-		if toTime, err := time.Parse("02.01.06", toStr); err == nil {
-			filter.ToDate = &toTime
-			h.logger.Info("фильтр по конечной дате", "to", toTime)
-		} else {
+		toTime, err := parseDateBound(toStr, true)
+		if err != nil {
 			h.logger.Warn("некорректный параметр to", "значение", toStr, "ошибка", err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный формат даты to, используйте ДД.ММ.ГГ (например: 25.12.24)"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
 			return
 		}
+		filter.ToDate = &toTime
+		h.logger.Info("фильтр по конечной дате", "to", toTime)
+	}
+
+	page, err := parsePageRequest(c, models.MatchSortPlayedAtDesc, models.MatchSortPlayedAtAsc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	matches, err := h.service.GetFiltered(filter)
+	matches, err := h.service.GetFiltered(filter, page)
 	if err != nil {
+		if errors.Is(err, cursor.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("ошибка при получении матчей", "ошибка", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch matches"})
 		return
 	}
 
-	h.logger.Info("матчи успешно получены")
+	h.logger.Info("матчи успешно получены", "count", len(matches.Data))
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": matches,
-	})
+	c.JSON(http.StatusOK, matches)
 }
 
 // CreateMatch godoc
 // @Summary Создать матч
-// @Description Записать результат матча
+// @Description Записать результат матча. Счёт передаётся по партиям ("11-9 7-11 11-5 11-8"),
+// @Description первым в каждой партии идёт счёт победителя. Формат (best-of-N, до 11/21) берётся из сезона.
+// @Description Для парного матча указываются winner_partner_id и loser_partner_id.
+// @Description Заявить результат может только участник матча; матч ждёт подтверждения соперника
+// @Description и до этого не влияет на рейтинги.
 // @Tags Matches
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param input body models.CreateMatchRequest true "Параметры матча"
 // @Success 201 {object} models.Match
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /matches [post]
 func (h *MatchHandler) CreateMatch(c *gin.Context) {
@@ -129,48 +241,175 @@ func (h *MatchHandler) CreateMatch(c *gin.Context) {
 		return
 	}
 
-	match, err := h.service.RecordMatch(req.WinnerID, req.LoserID, req.SeasonID, req.Score)
+	reporterID := c.GetUint("player_id")
+
+	match, err := h.service.RecordMatch(reporterID, &req)
 	if err != nil {
-		h.logger.Error("failed to record match", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record match"})
+		h.writeMatchError(c, err, "failed to record match")
 		return
 	}
 
 	c.JSON(http.StatusCreated, match)
 }
 
-func (h *MatchHandler) GetHeadToHead(c *gin.Context) {
-	playerIDStr := c.Param("id")
-	opponentIDStr := c.Param("opponentId")
-	limitStr := c.Param("limit")
+// ConfirmMatch godoc
+// @Summary Подтвердить матч
+// @Description Соперник заявившего игрока подтверждает результат, после чего матч учитывается в рейтингах и таблице
+// @Tags Matches
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID матча"
+// @Success 200 {object} models.Match
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /matches/{id}/confirm [post]
+func (h *MatchHandler) ConfirmMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match id"})
+		return
+	}
 
-	limit, err := strconv.Atoi(limitStr)
+	match, err := h.service.ConfirmMatch(uint(id), c.GetUint("player_id"))
 	if err != nil {
-		h.logger.Warn("invalid limit", "value", limitStr, "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		h.writeMatchError(c, err, "failed to confirm match")
 		return
 	}
 
-	playerID, err := strconv.ParseUint(playerIDStr, 10, 32)
-	if err != nil {
-		h.logger.Warn("invalid player ID", "value", playerIDStr, "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+	c.JSON(http.StatusOK, match)
+}
+
+// DisputeMatch godoc
+// @Summary Оспорить матч
+// @Description Соперник заявившего игрока отклоняет результат; матч не учитывается, пока его не исправят
+// @Tags Matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID матча"
+// @Param input body models.DisputeMatchRequest true "Причина"
+// @Success 200 {object} models.Match
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /matches/{id}/dispute [post]
+func (h *MatchHandler) DisputeMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match id"})
+		return
+	}
+
+	var req models.DisputeMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
 
-	opponentID, err := strconv.ParseUint(opponentIDStr, 10, 32)
+	match, err := h.service.DisputeMatch(uint(id), c.GetUint("player_id"), req.Reason)
 	if err != nil {
-		h.logger.Warn("invalid opponent ID", "value", opponentIDStr, "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opponent ID"})
+		h.writeMatchError(c, err, "failed to dispute match")
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
+// writeMatchError переводит ошибки сервиса матчей в HTTP-ответы.
+func (h *MatchHandler) writeMatchError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, score.ErrInvalidScore), errors.Is(err, service.ErrInvalidLineup):
+		h.logger.Warn("invalid match result", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "match, player or season not found"})
+	case errors.Is(err, service.ErrNotMatchParticipant), errors.Is(err, service.ErrReporterCannotReply),
+		errors.Is(err, service.ErrNotSeasonOrganizer), errors.Is(err, service.ErrEmailNotVerified):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMatchNotPending),
+		errors.Is(err, service.ErrSeasonClosed), errors.Is(err, service.ErrSeasonNotActive),
+		errors.Is(err, service.ErrOutsideSeasonDates):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(message, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// UpdateMatch godoc
+// @Summary Исправить матч
+// @Description Исправляет результат матча и пересчитывает рейтинги и таблицы по всем последующим матчам.
+// @Description Доступно администраторам и организатору сезона матча.
+// @Tags Matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID матча"
+// @Param input body models.UpdateMatchRequest true "Исправленные параметры матча"
+// @Success 200 {object} models.Match
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /matches/{id} [put]
+func (h *MatchHandler) UpdateMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match id"})
+		return
+	}
+
+	var req models.UpdateMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 		return
 	}
 
-	record, err := h.service.GetHeadToHead(uint(playerID), uint(opponentID), limit)
+	if req.WinnerID == req.LoserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "winner_id and loser_id must be different"})
+		return
+	}
+
+	match, err := h.service.UpdateMatch(uint(id), actorFrom(c), &req)
 	if err != nil {
-		h.logger.Error("failed to get head-to-head record", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get head-to-head record"})
+		h.writeMatchError(c, err, "failed to update match")
 		return
 	}
 
-	c.JSON(http.StatusOK, record)
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, match)
+}
+
+// DeleteMatch godoc
+// @Summary Аннулировать матч
+// @Description Удаляет матч и пересчитывает рейтинги и таблицы по всем последующим матчам.
+// @Description Доступно администраторам и организатору сезона матча.
+// @Tags Matches
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID матча"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /matches/{id} [delete]
+func (h *MatchHandler) DeleteMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match id"})
+		return
+	}
+
+	if err := h.service.DeleteMatch(uint(id), actorFrom(c)); err != nil {
+		h.writeMatchError(c, err, "failed to delete match")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -5,10 +5,14 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"shumnaya/internal/utils"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// TokenParser проверяет access-токен и возвращает ID игрока и его роль (jwtkeys.Manager).
+type TokenParser interface {
+	Parse(token string) (uint, string, error)
+}
+
+func AuthMiddleware(tokens TokenParser) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		auth := c.GetHeader("Authorization")
 		if auth == "" {
@@ -18,23 +22,42 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		parts := strings.Split(auth, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid authorization format",
-			})
-			return
-		}
+		authenticate(c, tokens, auth)
+	}
+}
 
-		userID, err := utils.ParseJWT(parts[1])
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid token",
-			})
+// OptionalAuth определяет игрока по токену, если он передан, и пропускает запрос
+// без авторизации. Неверный токен отклоняется так же, как в AuthMiddleware.
+func OptionalAuth(tokens TokenParser) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth := c.GetHeader("Authorization")
+		if auth == "" {
+			c.Next()
 			return
 		}
 
-		c.Set("player_id", uint(userID))
-		c.Next()
+		authenticate(c, tokens, auth)
 	}
 }
+
+func authenticate(c *gin.Context, tokens TokenParser, auth string) {
+	parts := strings.Split(auth, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "invalid authorization format",
+		})
+		return
+	}
+
+	playerID, role, err := tokens.Parse(parts[1])
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "invalid token",
+		})
+		return
+	}
+
+	c.Set("player_id", playerID)
+	c.Set("role", role)
+	c.Next()
+}
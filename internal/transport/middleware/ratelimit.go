@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"shumnaya/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit ограничивает частоту запросов корзиной limit на ключ scope + key(c).
+// Запрос сверх лимита получает 429 с заголовком Retry-After.
+func RateLimit(limiter *ratelimit.Limiter, scope string, limit ratelimit.Limit, key func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := limiter.Allow(scope+":"+key(c), limit); err != nil {
+			RejectLimited(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// ByIP — ключ лимита по адресу клиента.
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByPlayer — ключ лимита по авторизованному игроку; ставится после AuthMiddleware.
+func ByPlayer(c *gin.Context) string {
+	return "player:" + strconv.FormatUint(uint64(c.GetUint("player_id")), 10)
+}
+
+// RejectLimited отвечает 429 на превышение лимита или блокировку.
+func RejectLimited(c *gin.Context, err *ratelimit.LimitError) {
+	c.Header("Retry-After", strconv.Itoa(err.RetryAfterSeconds()))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+}
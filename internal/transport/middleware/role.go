@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole пропускает только игроков с одной из ролей roles (роль берётся из токена).
+// Должен стоять после AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(roles, c.GetString("role")) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "insufficient role",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"shumnaya/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Форматы дат в query-параметрах: RFC 3339, ISO-дата и исторический ДД.ММ.ГГ.
+var dateLayouts = []struct {
+	layout   string
+	dateOnly bool
+}{
+	{time.RFC3339, false},
+	{"2006-01-02", true},
+	{"02.01.06", true},
+}
+
+var errInvalidDate = errors.New("invalid date, expected RFC 3339 (2025-01-31T18:00:00Z), YYYY-MM-DD or ДД.ММ.ГГ")
+
+// parseDateBound разбирает дату из query-параметра. Для верхней границы (endOfDay)
+// дата без времени означает конец этого дня, чтобы матчи за последний день попадали в выборку.
+func parseDateBound(value string, endOfDay bool) (time.Time, error) {
+	for _, l := range dateLayouts {
+		t, err := time.Parse(l.layout, value)
+		if err != nil {
+			continue
+		}
+		if l.dateOnly && endOfDay {
+			t = t.Add(24*time.Hour - time.Nanosecond)
+		}
+		return t, nil
+	}
+	return time.Time{}, errInvalidDate
+}
+
+// parsePageRequest разбирает параметры страницы списка: limit (по умолчанию
+// models.DefaultPageLimit, больший models.MaxPageLimit урезается), cursor, sort из sorts
+// (первый — по умолчанию) и with_total.
+func parsePageRequest(c *gin.Context, sorts ...string) (models.PageRequest, error) {
+	page := models.PageRequest{Limit: models.DefaultPageLimit, Cursor: c.Query("cursor")}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return page, errors.New("invalid limit, expected a positive integer")
+		}
+		page.Limit = min(limit, models.MaxPageLimit)
+	}
+
+	if len(sorts) > 0 {
+		page.Sort = c.DefaultQuery("sort", sorts[0])
+		if !slices.Contains(sorts, page.Sort) {
+			return page, fmt.Errorf("invalid sort, expected one of: %s", strings.Join(sorts, ", "))
+		}
+	}
+
+	if totalStr := c.Query("with_total"); totalStr != "" {
+		withTotal, err := strconv.ParseBool(totalStr)
+		if err != nil {
+			return page, errors.New("invalid with_total format")
+		}
+		page.WithTotal = withTotal
+	}
+
+	return page, nil
+}
+
+// parseGamesScore разбирает счёт по партиям вида "3:0" (или "3-0"), победитель первым.
+func parseGamesScore(value string) (winnerGames, loserGames int, err error) {
+	errScore := errors.New("invalid score, expected winner and loser games like 3:0")
+
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == ':' || r == '-' })
+	if len(parts) != 2 {
+		return 0, 0, errScore
+	}
+	winnerGames, err = strconv.Atoi(parts[0])
+	if err != nil || winnerGames < 0 {
+		return 0, 0, errScore
+	}
+	loserGames, err = strconv.Atoi(parts[1])
+	if err != nil || loserGames < 0 {
+		return 0, 0, errScore
+	}
+	return winnerGames, loserGames, nil
+}
+
+// actorFrom возвращает авторизованного игрока запроса (после AuthMiddleware).
+func actorFrom(c *gin.Context) models.Actor {
+	return models.Actor{PlayerID: c.GetUint("player_id"), Role: c.GetString("role")}
+}
@@ -1,13 +1,20 @@
 package transport
 
 import (
+	"errors"
 	"log/slog"
+	"net/http"
 	"strconv"
 
 	"shumnaya/internal/dto"
+	"shumnaya/internal/models"
+	"shumnaya/internal/ratelimit"
 	"shumnaya/internal/service"
+	"shumnaya/internal/transport/middleware"
+	"shumnaya/internal/utils/cursor"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type PlayerHandler struct {
@@ -19,18 +26,19 @@ func NewPlayerHandler(r *gin.Engine, svc service.PlayerService, logger *slog.Log
 	return &PlayerHandler{service: svc, logger: logger}
 }
 
-func (h *PlayerHandler) RegisterRoutes(r *gin.Engine) {
-	r.GET("/players/:id", h.GetByID)
-	r.POST("/players", h.Register)
-}
-
 // GetByID godoc
 // @Summary Профиль игрока
+// @Description Публичный профиль (dto.PublicPlayerProfile) доступен любому авторизованному игроку.
+// @Description Свой профиль игрок получает в расширенном виде (dto.PrivatePlayerProfile) с данными учётной записи.
 // @Tags Players
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "ID игрока"
-// @Success 200 {object} models.PlayerProfile
+// @Param type query string false "Только одиночные или только парные матчи" Enums(singles, doubles)
+// @Success 200 {object} dto.PrivatePlayerProfile "Свой профиль; для чужого — dto.PublicPlayerProfile без account"
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /players/{id} [get]
 func (h *PlayerHandler) GetByID(c *gin.Context) {
@@ -43,18 +51,115 @@ func (h *PlayerHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	if uint(id) != tokenPlayerID {
-		c.JSON(403, gin.H{"error": "доступ запрещён"})
+	matchType := c.Query("type")
+	if matchType != "" && matchType != models.MatchTypeSingles && matchType != models.MatchTypeDoubles {
+		c.JSON(400, gin.H{"error": "invalid type"})
 		return
 	}
 
-	profile, err := h.service.GetPlayerProfile(uint(id))
+	profile, err := h.service.GetPlayerProfile(uint(id), matchType)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+			return
+		}
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(200, profile)
+	if uint(id) == tokenPlayerID {
+		c.JSON(200, dto.NewPrivatePlayerProfile(profile))
+		return
+	}
+	c.JSON(200, dto.NewPublicPlayerProfile(profile))
+}
+
+// List godoc
+// @Summary Список игроков
+// @Description Поиск игроков для выбора соперника: q ищет по началу имени и по похожему имени (триграммы).
+// @Description Возвращаются только публичные сведения — без email.
+// @Tags Players
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Имя или его начало"
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param sort query string false "Сортировка: name (по умолчанию), -name, -rating, rating, -last_active (недавно игравшие первыми), last_active" Enums(name, -name, -rating, rating, -last_active, last_active)
+// @Param with_total query bool false "Посчитать общее число игроков по запросу"
+// @Success 200 {object} models.PlayerSummaryPage
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players [get]
+func (h *PlayerHandler) List(c *gin.Context) {
+	page, err := parsePageRequest(c,
+		models.PlayerSortNameAsc, models.PlayerSortNameDesc,
+		models.PlayerSortRatingDesc, models.PlayerSortRatingAsc,
+		models.PlayerSortLastActiveDesc, models.PlayerSortLastActiveAsc,
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	players, err := h.service.SearchPlayers(c.Query("q"), page)
+	if err != nil {
+		if errors.Is(err, cursor.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("handler: ошибка поиска игроков", "query", c.Query("q"), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list players"})
+		return
+	}
+
+	c.JSON(http.StatusOK, players)
+}
+
+// SetRole godoc
+// @Summary Назначить роль игроку
+// @Description Роли: player — свои матчи, organizer — свои сезоны и их матчи, admin — всё.
+// @Description Новая роль действует со следующего входа игрока.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID игрока"
+// @Param input body dto.SetRoleRequest true "Роль"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/players/{id}/role [put]
+func (h *PlayerHandler) SetRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player id"})
+		return
+	}
+
+	var req dto.SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidRole.Error()})
+		return
+	}
+
+	if err := h.service.SetRole(uint(id), req.Role); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRole):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+		default:
+			h.logger.Error("handler: ошибка назначения роли", "player_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set role"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 // Register godoc
@@ -63,9 +168,10 @@ func (h *PlayerHandler) GetByID(c *gin.Context) {
 // @Tags Players
 // @Accept json
 // @Produce json
-// @Param input body RegisterPlayerRequest true "Данные регистрации"
-// @Success 201 {object} RegisterPlayerResponse
+// @Param input body dto.RegisterPlayerRequest true "Данные регистрации"
+// @Success 201 {object} dto.LoginResponse
 // @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string "Слишком много регистраций; см. Retry-After"
 // @Router /players [post]
 func (h *PlayerHandler) Register(c *gin.Context) {
 	var req dto.RegisterPlayerRequest
@@ -77,13 +183,19 @@ func (h *PlayerHandler) Register(c *gin.Context) {
 		return
 	}
 
-	token, err := h.service.RegisterPlayer(
+	tokens, err := h.service.RegisterPlayer(
 		req.Name,
 		req.Email,
 		req.Password,
+		c.ClientIP(),
 	)
 
 	if err != nil {
+		var limitErr *ratelimit.LimitError
+		if errors.As(err, &limitErr) {
+			middleware.RejectLimited(c, limitErr)
+			return
+		}
 		if h.logger != nil {
 			h.logger.Error(
 				"handler: ошибка регистрации игрока",
@@ -97,11 +209,22 @@ func (h *PlayerHandler) Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(201, gin.H{
-		"token": token,
-	})
+	c.JSON(201, dto.NewLoginResponse(tokens))
 }
 
+// Login godoc
+// @Summary Вход
+// @Description Возвращает короткоживущий access-токен и refresh-токен для POST /auth/refresh.
+// @Description После серии неудачных попыток вход по email (и с IP) блокируется, каждая следующая неудача удваивает блокировку.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body dto.LoginRequest true "Email и пароль"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string "Лимит попыток или блокировка; см. Retry-After"
+// @Router /login [post]
 func (h *PlayerHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
 
@@ -110,13 +233,16 @@ func (h *PlayerHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.service.Login(req.Email, req.Password)
+	tokens, err := h.service.Login(req.Email, req.Password, c.ClientIP())
 	if err != nil {
+		var limitErr *ratelimit.LimitError
+		if errors.As(err, &limitErr) {
+			middleware.RejectLimited(c, limitErr)
+			return
+		}
 		c.JSON(401, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(200, dto.LoginResponse{
-		Token: token,
-	})
+	c.JSON(200, dto.NewLoginResponse(tokens))
 }
@@ -0,0 +1,171 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/service"
+	"shumnaya/internal/utils/rating"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PredictionHandler struct {
+	service service.PredictionService
+	logger  *slog.Logger
+}
+
+func NewPredictionHandler(r *gin.Engine, svc service.PredictionService, logger *slog.Logger) *PredictionHandler {
+	return &PredictionHandler{service: svc, logger: logger}
+}
+
+func (h *PredictionHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/predict", h.Predict)
+}
+
+// Predict godoc
+// @Summary Прогноз матча
+// @Description Вероятность победы каждого игрока в одиночном матче и изменение их общего рейтинга при каждом исходе.
+// @Description Считается рейтинговой системой сезона (по умолчанию активного, без активного сезона — Elo) так же,
+// @Description как при записи результата.
+// @Tags Predictions
+// @Produce json
+// @Param player_a query int true "ID игрока A"
+// @Param player_b query int true "ID игрока B"
+// @Param season_id query int false "ID сезона, по правилам которого считать прогноз"
+// @Success 200 {object} models.MatchPrediction
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /predict [get]
+func (h *PredictionHandler) Predict(c *gin.Context) {
+	playerAID, err := strconv.ParseUint(c.Query("player_a"), 10, 32)
+	if err != nil || playerAID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player_a"})
+		return
+	}
+
+	playerBID, err := strconv.ParseUint(c.Query("player_b"), 10, 32)
+	if err != nil || playerBID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player_b"})
+		return
+	}
+
+	var seasonID *uint
+	if seasonIDStr := c.Query("season_id"); seasonIDStr != "" {
+		id, err := strconv.ParseUint(seasonIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season_id format"})
+			return
+		}
+		idUint := uint(id)
+		seasonID = &idUint
+	}
+
+	prediction, err := h.service.Predict(uint(playerAID), uint(playerBID), seasonID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSamePlayer):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "player or season not found"})
+		default:
+			h.logger.Error("failed to predict match", "player_a_id", playerAID, "player_b_id", playerBID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to predict match"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, prediction)
+}
+
+// GetCalibration godoc
+// @Summary Калибровка прогнозов
+// @Description Проверка прогнозов, сохранённых в подтверждённых матчах, на их исходах: Brier score (0 — идеально, 0.25 — монетка),
+// @Description улучшение относительно прогноза 50/50, log-loss и калибровка по корзинам прогноза.
+// @Description Прогноз берётся для стороны с меньшим ID игрока. Неявки и матчи без прогноза не учитываются.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param buckets query int false "Число корзин прогноза (по умолчанию 10, максимум 50)"
+// @Param season_id query int false "ID сезона"
+// @Param engine query string false "Рейтинговая система" Enums(elo, glicko2, trueskill)
+// @Param type query string false "Только одиночные или только парные матчи" Enums(singles, doubles)
+// @Param from query string false "Начало периода (RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ)" example(2025-01-01)
+// @Param to query string false "Конец периода (RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ)" example(2025-12-31)
+// @Success 200 {object} models.CalibrationReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/calibration [get]
+func (h *PredictionHandler) GetCalibration(c *gin.Context) {
+	buckets := 0
+	if bucketsStr := c.Query("buckets"); bucketsStr != "" {
+		var err error
+		buckets, err = strconv.Atoi(bucketsStr)
+		if err != nil || buckets <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid buckets"})
+			return
+		}
+	}
+
+	var filter models.CalibrationFilter
+
+	if seasonIDStr := c.Query("season_id"); seasonIDStr != "" {
+		seasonID, err := strconv.ParseUint(seasonIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season_id format"})
+			return
+		}
+		seasonIDUint := uint(seasonID)
+		filter.SeasonID = &seasonIDUint
+	}
+
+	if engine := c.Query("engine"); engine != "" {
+		if _, err := rating.New(engine, rating.Options{}); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter.Engine = &engine
+	}
+
+	if matchType := c.Query("type"); matchType != "" {
+		if matchType != models.MatchTypeSingles && matchType != models.MatchTypeDoubles {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, expected singles or doubles"})
+			return
+		}
+		filter.Type = &matchType
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseDateBound(fromStr, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+			return
+		}
+		filter.FromDate = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseDateBound(toStr, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+			return
+		}
+		filter.ToDate = &to
+	}
+
+	report, err := h.service.GetCalibration(filter, buckets)
+	if err != nil {
+		h.logger.Error("failed to get calibration report", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get calibration report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
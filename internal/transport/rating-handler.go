@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RatingHandler struct {
+	service service.RatingService
+	logger  *slog.Logger
+}
+
+func NewRatingHandler(r *gin.Engine, svc service.RatingService, logger *slog.Logger) *RatingHandler {
+	return &RatingHandler{service: svc, logger: logger}
+}
+
+func (h *RatingHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/players/:id/rating-history", h.GetRatingHistory)
+}
+
+// GetRatingHistory godoc
+// @Summary История рейтинга игрока
+// @Description Временной ряд рейтинга по подтверждённым матчам с пиком и минимумом за период
+// @Tags Players
+// @Produce json
+// @Param id path int true "ID игрока"
+// @Param from query string false "Начало периода (RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ)" example(2025-01-01)
+// @Param to query string false "Конец периода (RFC 3339, YYYY-MM-DD или ДД.ММ.ГГ)" example(2025-12-31)
+// @Param season_id query int false "ID сезона"
+// @Success 200 {object} models.RatingTimeline
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/rating-history [get]
+func (h *RatingHandler) GetRatingHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player id"})
+		return
+	}
+
+	filter := &models.RatingHistoryFilter{}
+
+	if seasonIDStr := c.Query("season_id"); seasonIDStr != "" {
+		seasonID, err := strconv.ParseUint(seasonIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season_id format"})
+			return
+		}
+		seasonIDUint := uint(seasonID)
+		filter.SeasonID = &seasonIDUint
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseDateBound(fromStr, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+			return
+		}
+		filter.FromDate = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseDateBound(toStr, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+			return
+		}
+		filter.ToDate = &to
+	}
+
+	timeline, err := h.service.GetRatingHistory(uint(id), filter)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+			return
+		}
+		h.logger.Error("failed to get rating history", "player_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get rating history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
@@ -2,8 +2,12 @@ package transport
 
 import (
 	"log/slog"
+	"shumnaya/internal/config"
+	"shumnaya/internal/models"
+	"shumnaya/internal/ratelimit"
 	"shumnaya/internal/service"
 	"shumnaya/internal/transport/middleware"
+	"shumnaya/internal/utils/jwtkeys"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,24 +16,81 @@ func RegisterRoutes(
 	r *gin.Engine,
 	matchService service.MatchService,
 	playerService service.PlayerService,
+	authService service.AuthService,
+	accountService service.AccountService,
 	seasonService service.SeasonService,
 	standingService service.StandingService,
+	ratingService service.RatingService,
+	statsService service.StatsService,
+	predictionService service.PredictionService,
+	leaderboardService service.LeaderboardService,
+	recomputeService service.RecomputeService,
+	seasonScheduler SeasonScheduler,
+	keyService service.SigningKeyService,
+	keys *jwtkeys.Manager,
+	limiter *ratelimit.Limiter,
+	limits config.RateLimitConfig,
 	logger *slog.Logger,
 ) {
 	matchHandler := NewMatchHandler(r, matchService, logger)
 	playerHandler := NewPlayerHandler(r, playerService, logger)
+	authHandler := NewAuthHandler(r, authService, logger)
+	accountHandler := NewAccountHandler(r, accountService, logger)
 	seasonHandler := NewSeasonHandler(r, seasonService, standingService, logger)
+	ratingHandler := NewRatingHandler(r, ratingService, logger)
+	statsHandler := NewStatsHandler(r, statsService, logger)
+	predictionHandler := NewPredictionHandler(r, predictionService, logger)
+	leaderboardHandler := NewLeaderboardHandler(r, leaderboardService, logger)
+	adminHandler := NewAdminHandler(r, recomputeService, seasonScheduler, logger)
+	keysHandler := NewKeysHandler(r, keys, keyService, logger)
 
 	// все как было
 	matchHandler.RegisterRoutes(r)
 	seasonHandler.RegisterRoutes(r)
+	ratingHandler.RegisterRoutes(r)
+	statsHandler.RegisterRoutes(r)
+	predictionHandler.RegisterRoutes(r)
+	authHandler.RegisterRoutes(r)
+	accountHandler.RegisterRoutes(r)
+	keysHandler.RegisterRoutes(r)
 
-	// 🔓 публичные
-	r.POST("/players", playerHandler.Register)
-	r.POST("/login", playerHandler.Login)
+	// 🔓 публичные; вход, регистрация и письма ограничены по IP (по email — в сервисах)
+	r.POST("/players", middleware.RateLimit(limiter, "register", limits.AccountPerIP, middleware.ByIP), playerHandler.Register)
+	r.POST("/login", middleware.RateLimit(limiter, "login", limits.LoginPerIP, middleware.ByIP), playerHandler.Login)
+	r.POST("/auth/password-reset/request",
+		middleware.RateLimit(limiter, "password-reset", limits.AccountPerIP, middleware.ByIP), accountHandler.RequestPasswordReset)
+	r.GET("/leaderboard", middleware.OptionalAuth(keys), leaderboardHandler.GetLeaderboard)
 
-	// 🔐 защищённые
+	// 🔐 защищённые: любой игрок; матчи заявляют, подтверждают и оспаривают только их участники,
+	// заявить матч можно только с подтверждённым email
 	auth := r.Group("/")
-	auth.Use(middleware.AuthMiddleware())
+	auth.Use(middleware.AuthMiddleware(keys))
+	// общий лимит изменяющих запросов одного игрока
+	writeLimit := middleware.RateLimit(limiter, "write", limits.WritePerPlayer, middleware.ByPlayer)
+	auth.POST("/auth/logout-all", authHandler.LogoutAll)
+	auth.POST("/auth/verify-email/request", writeLimit, accountHandler.RequestEmailVerification)
+	auth.GET("/players", playerHandler.List)
 	auth.GET("/players/:id", playerHandler.GetByID)
+	auth.POST("/matches", writeLimit, matchHandler.CreateMatch)
+	auth.POST("/matches/:id/confirm", writeLimit, matchHandler.ConfirmMatch)
+	auth.POST("/matches/:id/dispute", writeLimit, matchHandler.DisputeMatch)
+
+	// 🏓 организаторы: свои сезоны и исправления их матчей (принадлежность сезона проверяет сервис)
+	organizer := auth.Group("/")
+	organizer.Use(middleware.RequireRole(models.RoleOrganizer, models.RoleAdmin))
+	organizer.Use(writeLimit)
+	organizer.POST("/seasons", seasonHandler.create)
+	organizer.POST("/seasons/:id/activate", seasonHandler.activate)
+	organizer.POST("/seasons/:id/close", seasonHandler.close)
+	organizer.PUT("/matches/:id", matchHandler.UpdateMatch)
+	organizer.DELETE("/matches/:id", matchHandler.DeleteMatch)
+
+	// 🛠 администрирование
+	admin := auth.Group("/admin")
+	admin.Use(middleware.RequireRole(models.RoleAdmin))
+	admin.PUT("/players/:id/role", playerHandler.SetRole)
+	admin.POST("/recompute", adminHandler.Recompute)
+	admin.GET("/season-scheduler", adminHandler.SchedulerStatus)
+	admin.GET("/calibration", predictionHandler.GetCalibration)
+	admin.POST("/jwt/rotate", keysHandler.RotateKeys)
 }
@@ -1,12 +1,14 @@
 package transport
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
 	"shumnaya/internal/models"
 	"shumnaya/internal/service"
+	"shumnaya/internal/utils/cursor"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -35,20 +37,36 @@ func NewSeasonHandler(
 func (h *SeasonHandler) RegisterRoutes(r *gin.Engine) {
 	r.GET("/seasons", h.getAll)
 	r.GET("/seasons/:id", h.getByID)
-	r.POST("/seasons", h.create)
 	r.GET("/seasons/:id/standings", h.getByIDstandings)
+	r.GET("/seasons/:id/results", h.getResults)
 }
 
 // getAll godoc
-// @Summary Все сезоны
+// @Summary Сезоны
+// @Description Страница сезонов, упорядоченных по (start_date, id)
 // @Tags Seasons
 // @Produce json
-// @Success 200 {array} models.Season
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param sort query string false "Сортировка: -start_date (по умолчанию, новые первыми) или start_date" Enums(-start_date, start_date)
+// @Param with_total query bool false "Посчитать общее число сезонов"
+// @Success 200 {object} models.SeasonPage
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /seasons [get]
 func (h *SeasonHandler) getAll(c *gin.Context) {
-	seasons, err := h.service.GetAllSeasons()
+	page, err := parsePageRequest(c, models.SeasonSortStartDateDesc, models.SeasonSortStartDateAsc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seasons, err := h.service.GetSeasons(page)
 	if err != nil {
+		if errors.Is(err, cursor.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("handler: ошибка получения списка сезонов", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "не удалось получить сезоны"})
 		return
@@ -91,12 +109,18 @@ func (h *SeasonHandler) getByID(c *gin.Context) {
 
 // create godoc
 // @Summary Создать сезон
+// @Description Сезон создаётся неактивным; активируется через POST /seasons/{id}/activate.
+// @Description Создают сезоны организаторы и администраторы. Организатор становится организатором сезона,
+// @Description администратор может назначить организатора через organizer_id.
 // @Tags Seasons
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param input body models.Season true "Сезон"
 // @Success 201 {object} models.Season
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Router /seasons [post]
 func (h *SeasonHandler) create(c *gin.Context) {
 	var season models.Season
@@ -107,6 +131,11 @@ func (h *SeasonHandler) create(c *gin.Context) {
 		return
 	}
 
+	season.ID = 0 // id назначает база, а не клиент
+	if actor := actorFrom(c); actor.Role != models.RoleAdmin {
+		season.OrganizerID = &actor.PlayerID
+	}
+
 	if err := h.service.CreateSeason(&season); err != nil {
 		h.logger.Error("handler: ошибка создания сезона", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -121,7 +150,12 @@ func (h *SeasonHandler) create(c *gin.Context) {
 // @Tags Seasons
 // @Produce json
 // @Param id path int true "ID сезона"
-// @Success 200 {array} models.Standing
+// @Param type query string false "Разряд: singles (по умолчанию) или doubles" Enums(singles, doubles)
+// @Param order_by query string false "Порядок: points (по умолчанию) — по местам с учётом критериев сезона, rating — по сезонному рейтингу" Enums(points, rating)
+// @Param limit query int false "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor), действителен для того же order_by"
+// @Param with_total query bool false "Посчитать общее число строк таблицы"
+// @Success 200 {object} models.StandingPage
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /seasons/{id}/standings [get]
@@ -133,6 +167,24 @@ func (h *SeasonHandler) getByIDstandings(c *gin.Context) {
 		return
 	}
 
+	matchType := c.DefaultQuery("type", models.MatchTypeSingles)
+	if matchType != models.MatchTypeSingles && matchType != models.MatchTypeDoubles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный type, допустимо singles или doubles"})
+		return
+	}
+
+	orderBy := c.DefaultQuery("order_by", models.StandingsOrderPoints)
+	if orderBy != models.StandingsOrderPoints && orderBy != models.StandingsOrderRating {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный order_by, допустимо points или rating"})
+		return
+	}
+
+	page, err := parsePageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	season, err := h.service.GetSeasonByID(uint(id))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -145,24 +197,131 @@ func (h *SeasonHandler) getByIDstandings(c *gin.Context) {
 		return
 	}
 
-	standing, err := h.standing.GetSeasonStandings(season.ID)
+	standing, err := h.standing.GetSeasonStandings(season.ID, matchType, orderBy, page)
 
 	if err != nil {
+		if errors.Is(err, cursor.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": cursor.ErrInvalidCursor.Error()})
+			return
+		}
 		h.logger.Error("handler: ошибка при получении standings", "season_id", season.ID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "не удалось получить таблицу сезона"})
 		return
 	}
 
-	if len(standing) == 0 {
-		h.logger.Error("season standings пустые")
-		c.JSON(http.StatusOK, gin.H{
-			"season_id": id,
-			"standings": standing,
-			"count":     len(standing),
-		})
+	c.JSON(http.StatusOK, standing)
+
+}
+
+// activate godoc
+// @Summary Активировать сезон
+// @Description Активным может быть только один сезон; закрытый или закончившийся сезон активировать нельзя.
+// @Description Доступно администраторам и организатору сезона.
+// @Tags Seasons
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сезона"
+// @Success 200 {object} models.Season
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /seasons/{id}/activate [post]
+func (h *SeasonHandler) activate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный id"})
 		return
 	}
 
-	c.JSON(http.StatusOK, standing)
+	if err := h.service.CheckSeasonAccess(uint(id), actorFrom(c)); err != nil {
+		h.writeSeasonError(c, err, "не удалось активировать сезон")
+		return
+	}
+
+	season, err := h.service.ActivateSeason(uint(id))
+	if err != nil {
+		h.writeSeasonError(c, err, "не удалось активировать сезон")
+		return
+	}
 
+	c.JSON(http.StatusOK, season)
+}
+
+// close godoc
+// @Summary Закрыть сезон
+// @Description Фиксирует итоговые места, снимок таблиц и награды. После закрытия матчи сезона изменить нельзя.
+// @Description Доступно администраторам и организатору сезона.
+// @Tags Seasons
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сезона"
+// @Success 200 {object} models.SeasonSummary
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /seasons/{id}/close [post]
+func (h *SeasonHandler) close(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный id"})
+		return
+	}
+
+	if err := h.service.CheckSeasonAccess(uint(id), actorFrom(c)); err != nil {
+		h.writeSeasonError(c, err, "не удалось закрыть сезон")
+		return
+	}
+
+	summary, err := h.service.CloseSeason(uint(id))
+	if err != nil {
+		h.writeSeasonError(c, err, "не удалось закрыть сезон")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// getResults godoc
+// @Summary Итоги закрытого сезона
+// @Tags Seasons
+// @Produce json
+// @Param id path int true "ID сезона"
+// @Success 200 {object} models.SeasonSummary
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /seasons/{id}/results [get]
+func (h *SeasonHandler) getResults(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "некорректный id"})
+		return
+	}
+
+	summary, err := h.service.GetSeasonResults(uint(id))
+	if err != nil {
+		h.writeSeasonError(c, err, "не удалось получить итоги сезона")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// writeSeasonError переводит ошибки жизненного цикла сезона в HTTP-ответы.
+func (h *SeasonHandler) writeSeasonError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "сезон не найден"})
+	case errors.Is(err, service.ErrNotSeasonOrganizer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSeasonClosed), errors.Is(err, service.ErrSeasonEnded),
+		errors.Is(err, service.ErrAnotherSeasonActive), errors.Is(err, service.ErrSeasonHasPendingMatches),
+		errors.Is(err, service.ErrSeasonNotClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("handler: "+message, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
 }
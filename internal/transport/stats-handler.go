@@ -0,0 +1,160 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"shumnaya/internal/models"
+	"shumnaya/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type StatsHandler struct {
+	service service.StatsService
+	logger  *slog.Logger
+}
+
+func NewStatsHandler(r *gin.Engine, svc service.StatsService, logger *slog.Logger) *StatsHandler {
+	return &StatsHandler{service: svc, logger: logger}
+}
+
+func (h *StatsHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/players/:id/stats", h.GetPlayerStats)
+	r.GET("/players/:id/head-to-head/:opponentId", h.GetHeadToHead)
+	r.GET("/players/:id/vs/:opponentId/:limit", h.GetHeadToHeadLegacy)
+}
+
+// GetPlayerStats godoc
+// @Summary Статистика игрока
+// @Description Статистика по подтверждённым матчам: общий счёт, доли выигранных партий и очков, текущая и самые длинные серии,
+// @Description форма в последних 10 матчах, результаты против соперников сильнее и слабее по рейтингу перед матчем,
+// @Description разбивка по сезонам, любимые и неудобные соперники.
+// @Tags Players
+// @Produce json
+// @Param id path int true "ID игрока"
+// @Param type query string false "Только одиночные или только парные матчи" Enums(singles, doubles)
+// @Success 200 {object} models.PlayerStats
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/stats [get]
+func (h *StatsHandler) GetPlayerStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player id"})
+		return
+	}
+
+	matchType := c.Query("type")
+	if matchType != "" && matchType != models.MatchTypeSingles && matchType != models.MatchTypeDoubles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, expected singles or doubles"})
+		return
+	}
+
+	stats, err := h.service.GetPlayerStats(uint(id), matchType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+			return
+		}
+		h.logger.Error("failed to get player stats", "player_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get player stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetHeadToHead godoc
+// @Summary Личные встречи игроков
+// @Description Встречи игрока и соперника на разных сторонах подтверждённых матчей (в том числе в составе пар):
+// @Description счёт, партии и очки, суммарное изменение рейтинга каждого, серии внутри противостояния,
+// @Description разбивка по сезонам, последние встречи и прогноз следующей встречи по Эло.
+// @Tags Players
+// @Produce json
+// @Param id path int true "ID игрока (A)"
+// @Param opponentId path int true "ID соперника (B)"
+// @Param type query string false "Разряд: singles (по умолчанию) или doubles" Enums(singles, doubles)
+// @Param limit query int false "Сколько последних встреч вернуть (по умолчанию 5, максимум 50)"
+// @Success 200 {object} models.HeadToHeadRecord
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /players/{id}/head-to-head/{opponentId} [get]
+func (h *StatsHandler) GetHeadToHead(c *gin.Context) {
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+
+	h.headToHead(c, limit)
+}
+
+// GetHeadToHeadLegacy godoc
+// @Summary Личные встречи игроков (устаревший адрес)
+// @Description Используйте GET /players/{id}/head-to-head/{opponentId}?limit=
+// @Tags Players
+// @Produce json
+// @Param id path int true "ID игрока (A)"
+// @Param opponentId path int true "ID соперника (B)"
+// @Param limit path int true "Сколько последних встреч вернуть"
+// @Success 200 {object} models.HeadToHeadRecord
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Deprecated
+// @Router /players/{id}/vs/{opponentId}/{limit} [get]
+func (h *StatsHandler) GetHeadToHeadLegacy(c *gin.Context) {
+	limit, err := strconv.Atoi(c.Param("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	h.headToHead(c, limit)
+}
+
+func (h *StatsHandler) headToHead(c *gin.Context, limit int) {
+	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || playerID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return
+	}
+
+	opponentID, err := strconv.ParseUint(c.Param("opponentId"), 10, 32)
+	if err != nil || opponentID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid opponent ID"})
+		return
+	}
+
+	matchType := c.DefaultQuery("type", models.MatchTypeSingles)
+	if matchType != models.MatchTypeSingles && matchType != models.MatchTypeDoubles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, expected singles or doubles"})
+		return
+	}
+
+	record, err := h.service.GetHeadToHead(uint(playerID), uint(opponentID), matchType, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSamePlayer):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+		default:
+			h.logger.Error("failed to get head-to-head record", "player_id", playerID, "opponent_id", opponentID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get head-to-head record"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
@@ -0,0 +1,119 @@
+// Package accounttoken выпускает подписанные ссылки для действий с учётной записью:
+// подтверждения email и сброса пароля.
+//
+// Токен — "<payload>.<подпись>" в base64url, payload — "назначение:ID игрока:срок".
+// Подпись HMAC-SHA256 покрывает payload и состояние учётной записи (state), которое
+// меняет само действие: для сброса пароля — хеш пароля, для подтверждения — email и
+// флаг подтверждения. Поэтому токен одноразовый без хранения в базе: после
+// использования состояние другое и подпись больше не сходится.
+package accounttoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PurposeVerifyEmail   = "verify_email"
+	PurposeResetPassword = "reset_password"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid or expired token")
+	ErrNoSecret     = errors.New("account token secret is not configured")
+)
+
+// Claims — неподписанная часть токена. Ей нельзя доверять до Verify.
+type Claims struct {
+	Purpose   string
+	PlayerID  uint
+	ExpiresAt time.Time
+}
+
+func Sign(secret []byte, purpose string, playerID uint, state string, ttl time.Duration) (string, error) {
+	if len(secret) == 0 {
+		return "", ErrNoSecret
+	}
+
+	payload := fmt.Sprintf("%s:%d:%d", purpose, playerID, time.Now().Add(ttl).Unix())
+	sig := signature(secret, payload, state)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Parse читает назначение, игрока и срок без проверки подписи — чтобы найти игрока
+// и его текущее состояние для Verify. Истёкший токен и чужое назначение отклоняются сразу.
+func Parse(token, purpose string) (*Claims, error) {
+	payloadPart, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	fields := strings.Split(string(payload), ":")
+	if len(fields) != 3 || fields[0] != purpose {
+		return nil, ErrInvalidToken
+	}
+
+	playerID, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil || playerID == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	expires, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	claims := &Claims{Purpose: fields[0], PlayerID: uint(playerID), ExpiresAt: time.Unix(expires, 0)}
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// Verify проверяет подпись токена против текущего состояния учётной записи.
+func Verify(secret []byte, token, state string) error {
+	if len(secret) == 0 {
+		return ErrNoSecret
+	}
+
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if !hmac.Equal(sig, signature(secret, string(payload), state)) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func signature(secret []byte, payload, state string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	mac.Write([]byte{0})
+	mac.Write([]byte(state))
+	return mac.Sum(nil)
+}
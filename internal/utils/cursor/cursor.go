@@ -0,0 +1,31 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor — курсор повреждён или выдан для другой сортировки.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Encode упаковывает ключ последней строки страницы в непрозрачную строку для query-параметра.
+func Encode(key any) string {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode распаковывает курсор, полученный из Encode, в key.
+func Decode(value string, key any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, key); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
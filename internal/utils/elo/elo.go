@@ -6,8 +6,12 @@ func ExpectedScore(playerElo, opponentElo int) float64 {
 	return 1.0 / (1.0 + math.Pow(10, float64(opponentElo-playerElo)/400))
 }
 
+// ProvisionalGames — пока у игрока меньше матчей, его рейтинг считается предварительным
+// и меняется с повышенным K.
+const ProvisionalGames = 10
+
 func CalculateK(games, wins int) int {
-	if games < 10 {
+	if games < ProvisionalGames {
 		return 40
 	}
 
@@ -37,3 +41,54 @@ func NewRating(playerElo, opponentElo int, isWin bool, games, wins int) int {
 		float64(playerElo) + float64(k)*(score-expected),
 	))
 }
+
+// Способы свести рейтинги партнёров к рейтингу пары.
+const (
+	TeamRatingAverage   = "average"
+	TeamRatingStrongest = "strongest"
+	TeamRatingWeakest   = "weakest"
+)
+
+func TeamRating(ratings []int, mode string) int {
+	if len(ratings) == 0 {
+		return 0
+	}
+
+	switch mode {
+	case TeamRatingStrongest:
+		best := ratings[0]
+		for _, r := range ratings[1:] {
+			best = max(best, r)
+		}
+		return best
+	case TeamRatingWeakest:
+		worst := ratings[0]
+		for _, r := range ratings[1:] {
+			worst = min(worst, r)
+		}
+		return worst
+	default:
+		sum := 0
+		for _, r := range ratings {
+			sum += r
+		}
+		return int(math.Round(float64(sum) / float64(len(ratings))))
+	}
+}
+
+// NewTeamRating обновляет рейтинг игрока по результату командного матча: ожидание
+// считается по рейтингам пар, а K — по личной статистике игрока.
+func NewTeamRating(playerElo, teamElo, opponentTeamElo int, isWin bool, games, wins int) int {
+	expected := ExpectedScore(teamElo, opponentTeamElo)
+
+	score := 0.0
+	if isWin {
+		score = 1
+	}
+
+	k := CalculateK(games, wins)
+
+	return int(math.Round(
+		float64(playerElo) + float64(k)*(score-expected),
+	))
+}
@@ -1,52 +0,0 @@
-package utils
-
-import (
-	"errors"
-	"github.com/golang-jwt/jwt/v4"
-	"os"
-	"time"
-)
-
-func GenerateJWT(userID int64) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", errors.New("JWT_SECRET not set")
-	}
-
-	claims := jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(24 * time.Hour).Unix(),
-		"iat": time.Now().Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	return token.SignedString([]byte(secret))
-}
-
-func ParseJWT(tokenString string) (int64, error) {
-	secret := os.Getenv("JWT_SECRET")
-
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(secret), nil
-	})
-
-	if err != nil || !token.Valid {
-		return 0, errors.New("invalid token")
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return 0, errors.New("invalid claims")
-	}
-
-	userID, ok := claims["sub"].(float64)
-	if !ok {
-		return 0, errors.New("invalid sub")
-	}
-
-	return int64(userID), nil
-}
@@ -0,0 +1,152 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"shumnaya/internal/models"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const rsaKeyBits = 2048
+
+// signingKey — разобранный models.SigningKey.
+type signingKey struct {
+	kid         string
+	algorithm   string
+	method      jwt.SigningMethod
+	private     crypto.Signer
+	public      crypto.PublicKey
+	createdAt   time.Time
+	activatesAt time.Time
+	retired     bool
+}
+
+// GenerateKey создаёт новый ключ подписи. kid — случайный, чтобы ключи разных
+// экземпляров сервиса не пересекались.
+func GenerateKey(algorithm string, now time.Time) (*models.SigningKey, error) {
+	var private crypto.Signer
+	switch algorithm {
+	case models.SigningAlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	case models.SigningAlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	default:
+		return nil, fmt.Errorf("jwtkeys: unsupported algorithm %q", algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	kidBytes := make([]byte, 12)
+	if _, err := rand.Read(kidBytes); err != nil {
+		return nil, err
+	}
+
+	return &models.SigningKey{
+		KID:        base64.RawURLEncoding.EncodeToString(kidBytes),
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		CreatedAt:  now,
+	}, nil
+}
+
+func parseKey(stored models.SigningKey) (*signingKey, error) {
+	block, _ := pem.Decode([]byte(stored.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("jwtkeys: key %s: invalid private key PEM", stored.KID)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: key %s: %w", stored.KID, err)
+	}
+
+	key := &signingKey{
+		kid:         stored.KID,
+		algorithm:   stored.Algorithm,
+		createdAt:   stored.CreatedAt,
+		activatesAt: stored.ActivatesAt,
+		retired:     stored.RetiredAt != nil,
+	}
+
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		if stored.Algorithm != models.SigningAlgorithmRS256 {
+			return nil, fmt.Errorf("jwtkeys: key %s: RSA key stored as %s", stored.KID, stored.Algorithm)
+		}
+		key.method = jwt.SigningMethodRS256
+		key.private = private
+	case ed25519.PrivateKey:
+		if stored.Algorithm != models.SigningAlgorithmEdDSA {
+			return nil, fmt.Errorf("jwtkeys: key %s: Ed25519 key stored as %s", stored.KID, stored.Algorithm)
+		}
+		key.method = jwt.SigningMethodEdDSA
+		key.private = private
+	default:
+		return nil, fmt.Errorf("jwtkeys: key %s: unsupported key type %T", stored.KID, parsed)
+	}
+	key.public = key.private.Public()
+
+	return key, nil
+}
+
+// JWK — открытый ключ в формате RFC 7517.
+type JWK struct {
+	KID       string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Ed25519 (OKP)
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k *signingKey) jwk() JWK {
+	jwk := JWK{KID: k.kid, Algorithm: k.algorithm, Use: "sig"}
+
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return jwk
+}
@@ -0,0 +1,208 @@
+// Package jwtkeys выпускает и проверяет access-токены (JWT) набором ключей с kid:
+// RS256 или EdDSA, ротация без разлогинивания и публикация открытых ключей (JWKS)
+// для других сервисов. Ключи хранятся в базе (models.SigningKey); Manager держит
+// их копию в памяти, её обновляет service.SigningKeyService.
+package jwtkeys
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"shumnaya/internal/models"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrNoSigningKey  = errors.New("no active signing key")
+	errUnknownKey    = errors.New("unknown signing key")
+	errWrongIssuer   = errors.New("invalid issuer")
+	errWrongAudience = errors.New("invalid audience")
+)
+
+// JWKSCacheMaxAge — сколько потребители JWKS могут кешировать набор ключей.
+const JWKSCacheMaxAge = 5 * time.Minute
+
+// Claims — содержимое access-токена.
+type Claims struct {
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type Manager struct {
+	issuer   string
+	audience string
+
+	mu   sync.RWMutex
+	keys map[string]*signingKey
+	// signers — неотозванные ключи от поздней активации к ранней; подписывает первый
+	// наступивший, поэтому заранее загруженный следующий ключ вступает в силу вовремя
+	// и без перечитывания ключей.
+	signers []*signingKey
+}
+
+func NewManager(issuer, audience string) *Manager {
+	return &Manager{issuer: issuer, audience: audience, keys: make(map[string]*signingKey)}
+}
+
+// SetKeys заменяет набор ключей. Подписывает неотозванный ключ с самой поздней
+// наступившей активацией, остальные только проверяют токены.
+func (m *Manager) SetKeys(stored []models.SigningKey) error {
+	keys := make(map[string]*signingKey, len(stored))
+	var signers []*signingKey
+
+	for _, s := range stored {
+		key, err := parseKey(s)
+		if err != nil {
+			return err
+		}
+		keys[key.kid] = key
+
+		if !key.retired {
+			signers = append(signers, key)
+		}
+	}
+
+	sort.Slice(signers, func(i, j int) bool {
+		return signers[i].activatesAt.After(signers[j].activatesAt)
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keys = keys
+	m.signers = signers
+	return nil
+}
+
+// signingKey возвращает ключ, которым подписываются токены в момент now.
+func (m *Manager) signingKey(now time.Time) *signingKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.signers {
+		if !key.activatesAt.After(now) {
+			return key
+		}
+	}
+	return nil
+}
+
+// Sign выпускает access-токен игрока с его ролью на время ttl.
+func (m *Manager) Sign(playerID uint, role string, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	key := m.signingKey(now)
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	claims := Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   strconv.FormatUint(uint64(playerID), 10),
+			Audience:  jwt.ClaimStrings{m.audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	return token.SignedString(key.private)
+}
+
+// Parse проверяет подпись (ключом из заголовка kid), срок, iss и aud и возвращает
+// ID игрока и его роль. Токен без роли даёт роль models.RolePlayer.
+func (m *Manager) Parse(tokenString string) (uint, string, error) {
+	var claims Claims
+
+	token, err := jwt.ParseWithClaims(tokenString, &claims, m.keyFor)
+	if err != nil || !token.Valid {
+		return 0, "", ErrInvalidToken
+	}
+
+	if err := m.verifyClaims(&claims); err != nil {
+		return 0, "", ErrInvalidToken
+	}
+
+	playerID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || playerID == 0 {
+		return 0, "", ErrInvalidToken
+	}
+
+	role := claims.Role
+	if role == "" {
+		role = models.RolePlayer
+	}
+
+	return uint(playerID), role, nil
+}
+
+// keyFor выбирает ключ проверки по kid и не даёт подменить алгоритм ключа.
+func (m *Manager) keyFor(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	m.mu.RLock()
+	key, ok := m.keys[kid]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, errUnknownKey
+	}
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", t.Method.Alg(), kid)
+	}
+	return key.public, nil
+}
+
+func (m *Manager) verifyClaims(claims *Claims) error {
+	if !claims.VerifyIssuer(m.issuer, true) {
+		return errWrongIssuer
+	}
+	if !claims.VerifyAudience(m.audience, true) {
+		return errWrongAudience
+	}
+	// jwt/v4 не требует exp; токен без срока не принимается
+	if claims.ExpiresAt == nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// JWKS — открытые ключи всех действующих ключей (подписывающего, следующего и ещё
+// проверяющих), отсортированные от новых к старым.
+func (m *Manager) JWKS() JWKSet {
+	m.mu.RLock()
+	keys := make([]*signingKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].createdAt.After(keys[j].createdAt)
+	})
+
+	set := JWKSet{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, key.jwk())
+	}
+	return set
+}
+
+// SigningKeyID — kid ключа, которым сейчас подписываются токены.
+func (m *Manager) SigningKeyID() string {
+	key := m.signingKey(time.Now())
+	if key == nil {
+		return ""
+	}
+	return key.kid
+}
@@ -0,0 +1,45 @@
+package rating
+
+import (
+	"math"
+
+	"shumnaya/internal/utils/elo"
+)
+
+// eloSystem — классический Elo с K из elo.CalculateK; пары сводятся к одному рейтингу
+// по правилу сезона, а изменение считается для каждого партнёра отдельно.
+type eloSystem struct {
+	teamRating string
+}
+
+func (s *eloSystem) Name() string {
+	return EngineElo
+}
+
+func (s *eloSystem) WinProbability(a, b []Player) float64 {
+	return elo.ExpectedScore(s.team(a), s.team(b))
+}
+
+func (s *eloSystem) Rate(winners, losers []Player) ([]Player, []Player) {
+	winnerTeam := s.team(winners)
+	loserTeam := s.team(losers)
+
+	return s.rateSide(winners, winnerTeam, loserTeam, true), s.rateSide(losers, loserTeam, winnerTeam, false)
+}
+
+func (s *eloSystem) rateSide(side []Player, team, opponentTeam int, isWin bool) []Player {
+	rated := make([]Player, len(side))
+	for i, p := range side {
+		rated[i] = p
+		rated[i].Rating = float64(elo.NewTeamRating(int(math.Round(p.Rating)), team, opponentTeam, isWin, p.Games, p.Wins))
+	}
+	return rated
+}
+
+func (s *eloSystem) team(side []Player) int {
+	ratings := make([]int, len(side))
+	for i, p := range side {
+		ratings[i] = int(math.Round(p.Rating))
+	}
+	return elo.TeamRating(ratings, s.teamRating)
+}
@@ -0,0 +1,147 @@
+package rating
+
+import "math"
+
+const (
+	// glickoScale переводит рейтинг в шкалу Glicko-2 (μ, φ); центр шкалы совпадает со стартовым рейтингом.
+	glickoScale = 173.7178
+
+	defaultGlickoTau = 0.5
+	glickoEpsilon    = 0.000001
+)
+
+// glicko2System — Glicko-2 (Glickman, 2012), где каждый матч считается отдельным
+// рейтинговым периодом. В парном матче каждый игрок оценивается против «составного»
+// соперника: среднего рейтинга и среднеквадратичного отклонения пары соперников.
+type glicko2System struct {
+	tau float64
+}
+
+type glickoState struct {
+	mu, phi, sigma float64
+}
+
+func (s *glicko2System) Name() string {
+	return EngineGlicko2
+}
+
+func (s *glicko2System) WinProbability(a, b []Player) float64 {
+	ta, tb := composite(a), composite(b)
+	return glickoExpected(ta.mu, tb.mu, math.Sqrt(ta.phi*ta.phi+tb.phi*tb.phi))
+}
+
+func (s *glicko2System) Rate(winners, losers []Player) ([]Player, []Player) {
+	winnerTeam := composite(winners)
+	loserTeam := composite(losers)
+
+	rated := func(side []Player, opponent glickoState, score float64) []Player {
+		out := make([]Player, len(side))
+		for i, p := range side {
+			out[i] = s.update(p, opponent, score)
+		}
+		return out
+	}
+
+	return rated(winners, loserTeam, 1), rated(losers, winnerTeam, 0)
+}
+
+func (s *glicko2System) update(p Player, opponent glickoState, score float64) Player {
+	st := toGlicko(p)
+
+	g := glickoG(opponent.phi)
+	e := glickoExpected(st.mu, opponent.mu, opponent.phi)
+
+	v := 1 / (g * g * e * (1 - e))
+	delta := v * g * (score - e)
+
+	sigma := s.volatility(st, delta, v)
+
+	phiStar := math.Sqrt(st.phi*st.phi + sigma*sigma)
+	phi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu := st.mu + phi*phi*g*(score-e)
+
+	p.Rating = mu*glickoScale + InitialRating
+	p.Deviation = math.Min(phi*glickoScale, InitialDeviation)
+	p.Volatility = sigma
+	return p
+}
+
+// volatility — шаг 5 алгоритма: поиск новой волатильности методом Иллинойса.
+func (s *glicko2System) volatility(st glickoState, delta, v float64) float64 {
+	a := math.Log(st.sigma * st.sigma)
+	phi2 := st.phi * st.phi
+
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		num := ex * (delta*delta - phi2 - v - ex)
+		den := 2 * (phi2 + v + ex) * (phi2 + v + ex)
+		return num/den - (x-a)/(s.tau*s.tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi2+v {
+		B = math.Log(delta*delta - phi2 - v)
+	} else {
+		k := 1.0
+		for f(a-k*s.tau) < 0 {
+			k++
+		}
+		B = a - k*s.tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
+
+func toGlicko(p Player) glickoState {
+	deviation := p.Deviation
+	if deviation <= 0 {
+		deviation = InitialDeviation
+	}
+	volatility := p.Volatility
+	if volatility <= 0 {
+		volatility = InitialVolatility
+	}
+
+	return glickoState{
+		mu:    (p.Rating - InitialRating) / glickoScale,
+		phi:   deviation / glickoScale,
+		sigma: volatility,
+	}
+}
+
+func composite(side []Player) glickoState {
+	states := make([]glickoState, len(side))
+	for i, p := range side {
+		states[i] = toGlicko(p)
+	}
+
+	var mu, phi2 float64
+	for _, st := range states {
+		mu += st.mu
+		phi2 += st.phi * st.phi
+	}
+	n := float64(len(states))
+
+	return glickoState{mu: mu / n, phi: math.Sqrt(phi2 / n)}
+}
+
+func glickoG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func glickoExpected(mu, opponentMu, opponentPhi float64) float64 {
+	return 1 / (1 + math.Exp(-glickoG(opponentPhi)*(mu-opponentMu)))
+}
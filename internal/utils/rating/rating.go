@@ -0,0 +1,60 @@
+package rating
+
+import "fmt"
+
+// Поддерживаемые рейтинговые системы. Значение хранится в seasons.rating_engine.
+const (
+	EngineElo       = "elo"
+	EngineGlicko2   = "glicko2"
+	EngineTrueSkill = "trueskill"
+)
+
+const (
+	// InitialRating — стартовый рейтинг игрока во всех системах. Glicko-2 и TrueSkill
+	// переведены на ту же шкалу, что и Elo, чтобы рейтинг игрока оставался одним числом.
+	InitialRating = 1000
+
+	InitialDeviation  = 350.0
+	InitialVolatility = 0.06
+)
+
+// Player — состояние игрока перед матчем. Deviation и Volatility используются только
+// системами, которые их учитывают (Glicko-2 — оба, TrueSkill — Deviation как σ).
+// Games и Wins — сыгранные и выигранные матчи в сезоне, по ним Elo подбирает K.
+type Player struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+	Games      int
+	Wins       int
+}
+
+// System — рейтинговая система. Стороны матча передаются списками, чтобы одинаково
+// обрабатывать одиночные и парные матчи.
+type System interface {
+	Name() string
+
+	// WinProbability — ожидаемая вероятность победы стороны a над стороной b.
+	WinProbability(a, b []Player) float64
+
+	// Rate возвращает состояния игроков после победы winners над losers.
+	Rate(winners, losers []Player) (newWinners, newLosers []Player)
+}
+
+type Options struct {
+	// TeamRating — способ свести рейтинги партнёров к рейтингу пары (см. elo.TeamRating*).
+	TeamRating string
+}
+
+func New(engine string, opts Options) (System, error) {
+	switch engine {
+	case "", EngineElo:
+		return &eloSystem{teamRating: opts.TeamRating}, nil
+	case EngineGlicko2:
+		return &glicko2System{tau: defaultGlickoTau}, nil
+	case EngineTrueSkill:
+		return newTrueSkill(), nil
+	default:
+		return nil, fmt.Errorf("unknown rating engine %q", engine)
+	}
+}
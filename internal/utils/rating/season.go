@@ -0,0 +1,47 @@
+package rating
+
+import "fmt"
+
+// Политики сброса рейтинга в начале сезона. Значение хранится в seasons.rating_reset.
+const (
+	// ResetCarry — сезонный рейтинг начинается с текущего общего рейтинга игрока.
+	ResetCarry = "carry"
+	// ResetRegress — общий рейтинг сдвигается к стартовому на заданный процент.
+	ResetRegress = "regress"
+	// ResetHard — все начинают сезон со стартового рейтинга.
+	ResetHard = "hard"
+)
+
+// DefaultRegressPercent — насколько по умолчанию рейтинг сдвигается к стартовому при ResetRegress.
+const DefaultRegressPercent = 50
+
+// SeasonStart возвращает стартовое состояние игрока в сезоне по его общему рейтингу.
+// При ResetRegress рейтинг сдвигается к InitialRating на percent процентов, а
+// неопределённость — к начальной в той же пропорции.
+func SeasonStart(policy string, percent int, lifetime Player) (Player, error) {
+	start := Player{Rating: lifetime.Rating, Deviation: lifetime.Deviation, Volatility: lifetime.Volatility}
+
+	switch policy {
+	case "", ResetCarry:
+	case ResetRegress:
+		if percent < 0 || percent > 100 {
+			return Player{}, fmt.Errorf("regress percent must be between 0 and 100, got %d", percent)
+		}
+		k := float64(percent) / 100
+		start.Rating = lifetime.Rating + (InitialRating-lifetime.Rating)*k
+		start.Deviation = lifetime.Deviation + (InitialDeviation-lifetime.Deviation)*k
+	case ResetHard:
+		start = Player{Rating: InitialRating, Deviation: InitialDeviation, Volatility: InitialVolatility}
+	default:
+		return Player{}, fmt.Errorf("unknown rating reset policy %q", policy)
+	}
+
+	if start.Deviation <= 0 {
+		start.Deviation = InitialDeviation
+	}
+	if start.Volatility <= 0 {
+		start.Volatility = InitialVolatility
+	}
+
+	return start, nil
+}
@@ -0,0 +1,87 @@
+package rating
+
+import "math"
+
+// trueSkillSystem — TrueSkill (Herbrich, Minka, Graepel, 2006) для двух команд без ничьих.
+// Параметры переведены на шкалу Elo: μ₀ = 1000, σ₀ = μ₀/3. Рейтинг игрока хранит μ,
+// отклонение — σ.
+type trueSkillSystem struct {
+	beta float64
+	tau  float64
+}
+
+func newTrueSkill() *trueSkillSystem {
+	sigma0 := float64(InitialRating) / 3
+	return &trueSkillSystem{beta: sigma0 / 2, tau: sigma0 / 100}
+}
+
+func (s *trueSkillSystem) Name() string {
+	return EngineTrueSkill
+}
+
+func (s *trueSkillSystem) WinProbability(a, b []Player) float64 {
+	c := s.c(a, b)
+	return normCDF((teamMu(a) - teamMu(b)) / c)
+}
+
+func (s *trueSkillSystem) Rate(winners, losers []Player) ([]Player, []Player) {
+	// динамика: перед матчем неопределённость немного растёт
+	winners = s.withDynamics(winners)
+	losers = s.withDynamics(losers)
+
+	c := s.c(winners, losers)
+	t := (teamMu(winners) - teamMu(losers)) / c
+
+	v := normPDF(t) / math.Max(normCDF(t), 1e-12)
+	w := v * (v + t)
+
+	update := func(side []Player, sign float64) []Player {
+		out := make([]Player, len(side))
+		for i, p := range side {
+			sigma2 := p.Deviation * p.Deviation
+			p.Rating += sign * sigma2 / c * v
+			p.Deviation = math.Sqrt(sigma2 * math.Max(1-sigma2/(c*c)*w, 1e-6))
+			out[i] = p
+		}
+		return out
+	}
+
+	return update(winners, 1), update(losers, -1)
+}
+
+func (s *trueSkillSystem) withDynamics(side []Player) []Player {
+	out := make([]Player, len(side))
+	for i, p := range side {
+		sigma := p.Deviation
+		if sigma <= 0 {
+			sigma = float64(InitialRating) / 3
+		}
+		p.Deviation = math.Sqrt(sigma*sigma + s.tau*s.tau)
+		out[i] = p
+	}
+	return out
+}
+
+func (s *trueSkillSystem) c(a, b []Player) float64 {
+	sum := float64(len(a)+len(b)) * s.beta * s.beta
+	for _, p := range append(append([]Player{}, a...), b...) {
+		sum += p.Deviation * p.Deviation
+	}
+	return math.Sqrt(sum)
+}
+
+func teamMu(side []Player) float64 {
+	mu := 0.0
+	for _, p := range side {
+		mu += p.Rating
+	}
+	return mu
+}
+
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
@@ -0,0 +1,153 @@
+package score
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidScore — общая ошибка валидации счёта, остальные ошибки пакета её оборачивают.
+var ErrInvalidScore = errors.New("invalid score")
+
+const (
+	DefaultBestOf      = 5
+	DefaultPointsToWin = 11
+	DefaultWinBy       = 2
+)
+
+// Rules описывает формат матча: до скольких партий играем и до скольких очков партия.
+type Rules struct {
+	BestOf      int
+	PointsToWin int
+	WinBy       int
+}
+
+func DefaultRules() Rules {
+	return Rules{BestOf: DefaultBestOf, PointsToWin: DefaultPointsToWin, WinBy: DefaultWinBy}
+}
+
+// Game — счёт одной партии с точки зрения победителя матча.
+type Game struct {
+	WinnerPoints int
+	LoserPoints  int
+}
+
+type Result struct {
+	Games       []Game
+	WinnerGames int
+	LoserGames  int
+}
+
+func (r Rules) Validate() error {
+	if r.BestOf <= 0 || r.BestOf%2 == 0 {
+		return fmt.Errorf("%w: best_of must be a positive odd number, got %d", ErrInvalidScore, r.BestOf)
+	}
+	if r.PointsToWin <= 0 {
+		return fmt.Errorf("%w: points_to_win must be positive, got %d", ErrInvalidScore, r.PointsToWin)
+	}
+	if r.WinBy <= 0 {
+		return fmt.Errorf("%w: win_by must be positive, got %d", ErrInvalidScore, r.WinBy)
+	}
+	return nil
+}
+
+// GamesToWin — сколько партий нужно выиграть, чтобы взять матч.
+func (r Rules) GamesToWin() int {
+	return r.BestOf/2 + 1
+}
+
+// Parse разбирает счёт вида "11-9 7-11 11-5" (или через запятую, "11:9, 7:11, 11:5").
+// Первое число в каждой партии — очки победителя матча. Парсер проверяет каждую партию
+// по правилам и то, что победитель матча действительно выиграл нужное число партий.
+func Parse(raw string, rules Rules) (*Result, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ' ' || r == ',' || r == ';'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: score is empty", ErrInvalidScore)
+	}
+	if len(fields) > rules.BestOf {
+		return nil, fmt.Errorf("%w: %d games played, best of %d allows at most %d", ErrInvalidScore, len(fields), rules.BestOf, rules.BestOf)
+	}
+
+	need := rules.GamesToWin()
+	result := &Result{Games: make([]Game, 0, len(fields))}
+
+	for i, field := range fields {
+		if result.WinnerGames == need || result.LoserGames == need {
+			return nil, fmt.Errorf("%w: game %d played after the match was already decided", ErrInvalidScore, i+1)
+		}
+
+		game, err := parseGame(field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: game %d: %v", ErrInvalidScore, i+1, err)
+		}
+		if err := rules.validateGame(game); err != nil {
+			return nil, fmt.Errorf("%w: game %d (%s): %v", ErrInvalidScore, i+1, field, err)
+		}
+
+		if game.WinnerPoints > game.LoserPoints {
+			result.WinnerGames++
+		} else {
+			result.LoserGames++
+		}
+		result.Games = append(result.Games, game)
+	}
+
+	if result.WinnerGames != need {
+		return nil, fmt.Errorf("%w: winner must take %d games, got %d:%d", ErrInvalidScore, need, result.WinnerGames, result.LoserGames)
+	}
+
+	return result, nil
+}
+
+func parseGame(field string) (Game, error) {
+	sep := strings.IndexAny(field, "-:")
+	if sep <= 0 || sep == len(field)-1 {
+		return Game{}, fmt.Errorf("expected points as \"a-b\", got %q", field)
+	}
+
+	a, err := strconv.Atoi(field[:sep])
+	if err != nil || a < 0 {
+		return Game{}, fmt.Errorf("invalid points %q", field[:sep])
+	}
+	b, err := strconv.Atoi(field[sep+1:])
+	if err != nil || b < 0 {
+		return Game{}, fmt.Errorf("invalid points %q", field[sep+1:])
+	}
+
+	return Game{WinnerPoints: a, LoserPoints: b}, nil
+}
+
+func (r Rules) validateGame(g Game) error {
+	hi, lo := g.WinnerPoints, g.LoserPoints
+	if lo > hi {
+		hi, lo = lo, hi
+	}
+
+	switch {
+	case hi == lo:
+		return errors.New("a game cannot end in a draw")
+	case hi < r.PointsToWin:
+		return fmt.Errorf("a game is played to %d points", r.PointsToWin)
+	case hi-lo < r.WinBy:
+		return fmt.Errorf("a game must be won by %d points", r.WinBy)
+	case hi > r.PointsToWin && hi-lo != r.WinBy:
+		return fmt.Errorf("after %d points the game ends as soon as the lead reaches %d", r.PointsToWin, r.WinBy)
+	}
+	return nil
+}
+
+// String возвращает нормализованную запись счёта, которая хранится в matches.score.
+func (r *Result) String() string {
+	parts := make([]string, len(r.Games))
+	for i, g := range r.Games {
+		parts[i] = fmt.Sprintf("%d-%d", g.WinnerPoints, g.LoserPoints)
+	}
+	return strings.Join(parts, " ")
+}
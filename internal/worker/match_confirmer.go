@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"shumnaya/internal/config"
+	"shumnaya/internal/service"
+)
+
+// MatchConfirmer периодически подтверждает матчи, на которые соперник не ответил за отведённое время.
+type MatchConfirmer struct {
+	service service.MatchService
+	logger  *slog.Logger
+	cfg     config.MatchConfirmationConfig
+}
+
+func NewMatchConfirmer(svc service.MatchService, logger *slog.Logger, cfg config.MatchConfirmationConfig) *MatchConfirmer {
+	return &MatchConfirmer{service: svc, logger: logger, cfg: cfg}
+}
+
+// Run блокируется до отмены ctx.
+func (w *MatchConfirmer) Run(ctx context.Context) {
+	w.logger.Info("воркер автоподтверждения матчей запущен",
+		"timeout", w.cfg.Timeout.String(), "interval", w.cfg.Interval.String())
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.tick()
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("воркер автоподтверждения матчей остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *MatchConfirmer) tick() {
+	confirmed, err := w.service.AutoConfirmExpired(w.cfg.Timeout)
+	if err != nil {
+		w.logger.Error("ошибка автоподтверждения матчей", "error", err)
+		return
+	}
+
+	if confirmed > 0 {
+		w.logger.Info("матчи подтверждены автоматически", "count", confirmed)
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"shumnaya/internal/ratelimit"
+)
+
+// RateLimitSweeper периодически удаляет полные корзины и истёкшие блокировки,
+// чтобы хранилище лимитов не росло с каждым новым IP и email.
+type RateLimitSweeper struct {
+	limiter  *ratelimit.Limiter
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+func NewRateLimitSweeper(limiter *ratelimit.Limiter, logger *slog.Logger, interval time.Duration) *RateLimitSweeper {
+	return &RateLimitSweeper{limiter: limiter, logger: logger, interval: interval}
+}
+
+// Run блокируется до отмены ctx.
+func (w *RateLimitSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		removed, err := w.limiter.Sweep()
+		if err != nil {
+			w.logger.Error("ошибка очистки хранилища лимитов", "error", err)
+			continue
+		}
+		if removed > 0 {
+			w.logger.Debug("хранилище лимитов очищено", "removed", removed)
+		}
+	}
+}
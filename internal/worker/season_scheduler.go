@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"shumnaya/internal/config"
+	"shumnaya/internal/models"
+	"shumnaya/internal/service"
+)
+
+// schedulerLogSize — сколько последних действий планировщика хранится для админки.
+const schedulerLogSize = 200
+
+// SeasonScheduler периодически закрывает закончившиеся сезоны, активирует начавшиеся
+// и создаёт повторяющиеся сезоны по шаблону.
+type SeasonScheduler struct {
+	service service.SeasonService
+	logger  *slog.Logger
+	cfg     config.SeasonSchedulerConfig
+
+	mu        sync.Mutex
+	lastRunAt *time.Time
+	actions   []models.SchedulerAction
+}
+
+func NewSeasonScheduler(svc service.SeasonService, logger *slog.Logger, cfg config.SeasonSchedulerConfig) *SeasonScheduler {
+	return &SeasonScheduler{service: svc, logger: logger, cfg: cfg}
+}
+
+// Run блокируется до отмены ctx.
+func (w *SeasonScheduler) Run(ctx context.Context) {
+	w.logger.Info("планировщик сезонов запущен",
+		"interval", w.cfg.Interval.String(), "recurrence", w.cfg.Recurrence)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.tick()
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("планировщик сезонов остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *SeasonScheduler) tick() {
+	now := time.Now()
+
+	actions, err := w.service.RunSchedule(now, w.cfg.Recurrence)
+	if err != nil {
+		w.logger.Error("ошибка планировщика сезонов", "error", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastRunAt = &now
+	w.actions = append(w.actions, actions...)
+	if len(w.actions) > schedulerLogSize {
+		w.actions = append([]models.SchedulerAction(nil), w.actions[len(w.actions)-schedulerLogSize:]...)
+	}
+}
+
+// Status возвращает настройки планировщика и его последние действия, новые первыми.
+func (w *SeasonScheduler) Status() models.SchedulerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	actions := make([]models.SchedulerAction, len(w.actions))
+	for i, a := range w.actions {
+		actions[len(w.actions)-1-i] = a
+	}
+
+	return models.SchedulerStatus{
+		Interval:   w.cfg.Interval.String(),
+		Recurrence: w.cfg.Recurrence,
+		LastRunAt:  w.lastRunAt,
+		Actions:    actions,
+	}
+}
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"shumnaya/internal/service"
+)
+
+// SigningKeyRotator по расписанию ротирует ключи подписи токенов и подхватывает
+// ключи, выпущенные другими экземплярами сервиса.
+type SigningKeyRotator struct {
+	service  service.SigningKeyService
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+func NewSigningKeyRotator(svc service.SigningKeyService, logger *slog.Logger, interval time.Duration) *SigningKeyRotator {
+	return &SigningKeyRotator{service: svc, logger: logger, interval: interval}
+}
+
+// Run блокируется до отмены ctx. Первая синхронизация выполняется при запуске сервера, до Run.
+func (w *SigningKeyRotator) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := w.service.Sync(); err != nil {
+			w.logger.Error("ошибка ротации ключей подписи", "error", err)
+		}
+	}
+}